@@ -0,0 +1,64 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"viewer/main/http"
+	"viewer/main/repository"
+)
+
+func TestAssetFilters(t *testing.T) {
+	model := &repository.GithubReleaseModel{Assets: []repository.Asset{
+		{Name: "app-linux-amd64.tar.gz", ContentType: "application/gzip"},
+		{Name: "app-linux-amd64.tar.gz.sha256", ContentType: "text/plain"},
+		{Name: "app-darwin-arm64.zip", ContentType: "application/zip"},
+		{Name: "app-windows-amd64.zip", ContentType: "application/zip"},
+	}}
+	cases := []struct {
+		filter   repository.AssetFilter
+		expected []int
+		err      error
+	}{
+		{repository.AssetFilter{Glob: "*linux*"}, []int{0, 1}, nil},
+		{repository.AssetFilter{Glob: "*linux*", Exclude: []string{"*.sha256"}}, []int{0}, nil},
+		{repository.AssetFilter{Regex: `amd64\.zip$`}, []int{3}, nil},
+		{repository.AssetFilter{ContentType: "application/zip"}, []int{2, 3}, nil},
+		{repository.AssetFilter{ContentType: "application/"}, []int{0, 2, 3}, nil},
+		{repository.AssetFilter{Glob: "*.zip", Limit: 1}, nil, http.ErrTooManyAssets},
+		{repository.AssetFilter{Glob: "*freebsd*"}, nil, http.ErrNoMatchingAsset},
+	}
+	for _, c := range cases {
+		indexes, err := model.Filter(c.filter)
+		if !errors.Is(err, c.err) || !slices.Equal(indexes, c.expected) {
+			t.Errorf("Unexpected result for %s: %v (%v)", c.filter, indexes, err)
+		}
+	}
+	if index, err := model.Select(repository.AssetFilter{Glob: "*darwin*"}); index != 2 || err != nil {
+		t.Errorf("Unexpected selected asset %d (%v)", index, err)
+	}
+	if _, err := model.Filter(repository.AssetFilter{Regex: "("}); err == nil {
+		t.Error("Expected an error for the invalid regex.")
+	}
+}
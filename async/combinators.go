@@ -0,0 +1,83 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package async
+
+import (
+	"errors"
+)
+
+// ErrNoFutures This error is provided by Any when no futures are given.
+var ErrNoFutures = errors.New("async: no futures given")
+
+// All This function returns a new Future completed with the values of all the given futures, in the same order, once all
+// of them are completed. If any of them fails, the values of the failed ones are zero-values, and the Future's error joins
+// all the failures' errors.
+func All[T any](futures ...*Future[T]) *Future[[]T] {
+	return NewFuture(func() ([]T, error) {
+		values := make([]T, len(futures))
+		errs := make([]error, 0)
+		for index, future := range futures {
+			value, err := future.Get()
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			values[index] = value
+		}
+		return values, errors.Join(errs...)
+	})
+}
+
+// Any This function returns a new Future completed with the value of the first given future that succeeds. If all of them
+// fail, the Future's error joins all the failures' errors, or is ErrNoFutures if no futures are given.
+func Any[T any](futures ...*Future[T]) *Future[T] {
+	if len(futures) == 0 {
+		var zero T
+		return Completed(zero, ErrNoFutures)
+	}
+	result := &Future[T]{done: make(chan struct{})}
+	go func() {
+		errs := make([]error, len(futures))
+		failed := make(chan int, len(futures))
+		for index, future := range futures {
+			go func() {
+				value, err := future.Get()
+				if err != nil {
+					errs[index] = err
+					failed <- index
+					return
+				}
+				result.complete(value, nil)
+			}()
+		}
+		for range futures {
+			select {
+			case <-failed:
+			case <-result.done:
+				return
+			}
+		}
+		var zero T
+		result.complete(zero, errors.Join(errs...))
+	}()
+	return result
+}
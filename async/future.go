@@ -22,39 +22,122 @@
 package async
 
 import (
-	http2 "net/http"
+	"context"
+	"fmt"
 	"sync"
-	"viewer/main/http"
+	"time"
 )
 
-// Future This struct is used to represents and manages the result of an asynchronous computation.
-type Future[R http2.Response | http.ResponseModel] struct {
-	result chan R
+// Future This struct is used to represents and manages the result of an asynchronous computation, which is a value or the
+// error that caused the computation's failure. The result is memoized, so it can be requested any number of times and from
+// any number of goroutines.
+type Future[T any] struct {
+	once  sync.Once
+	done  chan struct{}
+	value T
+	err   error
 }
 
-// NewFuture This method creates a new Future object using the specified function, this function may return a value, or nil.
-func NewFuture[R http2.Response | http.ResponseModel](fn func() *R) Future[R] {
-	rwMut := sync.RWMutex{}
-	f := Future[R]{make(chan R)}
-	rwMut.RLock()
-	// Use goroutines for channel-to-channel communication.
+// NewFuture This function creates a new Future object using the specified function, which is executed in a new goroutine.
+func NewFuture[T any](fn func() (T, error)) *Future[T] {
+	return NewFutureContext(context.Background(), func(context.Context) (T, error) {
+		return fn()
+	})
+}
+
+// NewFutureContext This function creates a new Future object using the specified function, which receives the given context
+// and should stop its work when the context is done. If the context is done before the function returns, the Future is
+// completed with the context's error, and the function's result is discarded, but the function keeps running until it
+// returns, use Submit to wait for it. A panic in the function completes the Future with an error instead of crashing the
+// program.
+func NewFutureContext[T any](ctx context.Context, fn func(context.Context) (T, error)) *Future[T] {
+	f := &Future[T]{done: make(chan struct{})}
 	go func() {
-		result := fn()
-		// Avoid dereferencing for a null pointer
-		if result == nil {
-			result = new(R)
-		}
-		// Give to channel the pointer's value.
-		f.result <- *result
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				var zero T
+				f.complete(zero, fmt.Errorf("async: function panicked: %v", recovered))
+			}
+		}()
+		f.complete(fn(ctx))
 	}()
-	defer rwMut.RUnlock()
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				var zero T
+				f.complete(zero, ctx.Err())
+			case <-f.done:
+			}
+		}()
+	}
+	return f
+}
+
+// Completed This function creates a new Future object that is already completed with the given value and error.
+func Completed[T any](value T, err error) *Future[T] {
+	f := &Future[T]{done: make(chan struct{})}
+	f.complete(value, err)
 	return f
 }
 
-// Get This method returns this Future's channel's value (result) when it becomes available, this value may be nil depending on
-// the operation's use
-func (f *Future[R]) Get() *R {
-	value := <-f.result
-	defer close(f.result)
-	return &value
+// complete This method stores the given result and releases the waiting goroutines, only the first result is stored.
+func (f *Future[T]) complete(value T, err error) {
+	f.once.Do(func() {
+		f.value = value
+		f.err = err
+		close(f.done)
+	})
+}
+
+// Done This method returns a channel that is closed when this Future's result is available.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Get This method waits until this Future's result is available, and returns it.
+func (f *Future[T]) Get() (T, error) {
+	<-f.done
+	return f.value, f.err
+}
+
+// GetContext This method realizes the same execution that Get, if the given context is done before the result is available,
+// the context's error is returned instead, and the Future is not modified.
+func (f *Future[T]) GetContext(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// GetWithTimeout This method realizes the same execution that Get, if the result is not available after the given timeout,
+// context.DeadlineExceeded is returned instead, and the Future is not modified.
+func (f *Future[T]) GetWithTimeout(timeout time.Duration) (T, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return f.GetContext(ctx)
+}
+
+// Then This function returns a new Future completed with the result of the given function, which is executed with the
+// given Future's value once it's available. If the given Future fails, the function is not executed and its error is
+// propagated.
+func Then[T, R any](f *Future[T], fn func(T) (R, error)) *Future[R] {
+	return NewFuture(func() (R, error) {
+		value, err := f.Get()
+		if err != nil {
+			var zero R
+			return zero, err
+		}
+		return fn(value)
+	})
+}
+
+// Map This function realizes the same execution that Then, for functions that can't fail.
+func Map[T, R any](f *Future[T], fn func(T) R) *Future[R] {
+	return Then(f, func(value T) (R, error) {
+		return fn(value), nil
+	})
 }
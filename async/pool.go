@@ -0,0 +1,79 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package async
+
+import (
+	"context"
+)
+
+// Pool This struct is used to limit the amount of functions executed concurrently, the functions are submitted with Submit,
+// and a single Pool can be shared between multiple layers (e.g. requests and downloads) to apply a global limit.
+type Pool struct {
+	slots chan struct{}
+}
+
+// NewPool This function creates a new Pool that executes up to the given amount of functions concurrently, a limit lower
+// than one is considered as one.
+func NewPool(limit int) *Pool {
+	return &Pool{slots: make(chan struct{}, max(limit, 1))}
+}
+
+// Limit This method returns the maximum amount of functions executed concurrently by this Pool.
+func (p *Pool) Limit() int {
+	return cap(p.slots)
+}
+
+// Submit This function returns a new Future completed with the result of the given function, which is executed once the
+// given Pool has a free slot. If the given context is done before, the function is not executed, and the Future is completed
+// with the context's error. Otherwise, the Future is only completed once the function returns, even if the context is done
+// meanwhile, so the function must stop its work when the context is done.
+func Submit[T any](ctx context.Context, pool *Pool, fn func(context.Context) (T, error)) *Future[T] {
+	return NewFuture(func() (T, error) {
+		var zero T
+		select {
+		case pool.slots <- struct{}{}:
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+		defer func() {
+			<-pool.slots
+		}()
+		// The slot may be acquired at the same time that the context is done.
+		if ctx.Err() != nil {
+			return zero, ctx.Err()
+		}
+		return fn(ctx)
+	})
+}
+
+// Execute This function executes the given functions using a new Pool with the given limit, and waits until all of them
+// are completed, providing their values in the same order and an error that joins all the failures' errors, as All does.
+// When the given context is done, the pending functions are not executed, and the running ones are waited until they
+// return.
+func Execute[T any](ctx context.Context, limit int, fns ...func(context.Context) (T, error)) ([]T, error) {
+	pool := NewPool(limit)
+	futures := make([]*Future[T], len(fns))
+	for index, fn := range fns {
+		futures[index] = Submit(ctx, pool, fn)
+	}
+	return All(futures...).Get()
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"viewer/main/download"
+	"viewer/main/http"
+	"viewer/main/repository"
+)
+
+// useTestConfig This function points the http.DefaultConfig to the given server with the given token, restoring the
+// previous configuration when the test finishes.
+func useTestConfig(t *testing.T, server *httptest.Server, token string) {
+	previous := http.DefaultConfig
+	config, err := http.NewConfig(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Token = token
+	http.DefaultConfig = config
+	t.Cleanup(func() { http.DefaultConfig = previous })
+}
+
+func TestTokenAuthentication(t *testing.T) {
+	var storageAuthorization string
+	storage := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		storageAuthorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte("asset-content"))
+	}))
+	defer storage.Close()
+	api := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(nethttp.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/repos/aivruu/private-viewer":
+			_, _ = w.Write([]byte(`{"name":"private-viewer","private":true}`))
+		case "/asset":
+			// Redirect to another host, like the API does for the assets' storage.
+			nethttp.Redirect(w, r, strings.Replace(storage.URL, "127.0.0.1", "localhost", 1)+"/file", nethttp.StatusFound)
+		default:
+			w.WriteHeader(nethttp.StatusNotFound)
+		}
+	}))
+	defer api.Close()
+	useTestConfig(t, api, "secret")
+
+	model, err := http.Request(repository.NewRepositoryRequest(ForRepository("aivruu", "private-viewer")), 5*time.Second)
+	if err != nil || !model.Private {
+		t.Fatal("Failed to request the private repository using the token.", err)
+	}
+	directory := t.TempDir()
+	status, err := download.From(directory, "asset.bin", api.URL+"/asset")
+	if err != nil || !status.Downloaded() {
+		t.Fatalf("Failed to download the private asset, status '%d': %v", status.Status, err)
+	}
+	if storageAuthorization != "" {
+		t.Errorf("Token was leaked to the redirect's target: '%s'.", storageAuthorization)
+	}
+	content, _ := os.ReadFile(filepath.Join(directory, "asset.bin"))
+	if string(content) != "asset-content" {
+		t.Errorf("Unexpected asset content: '%s'.", content)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"viewer/main/async"
+	"viewer/main/download"
+	"viewer/main/http"
+)
+
+func TestParseBandwidth(t *testing.T) {
+	cases := map[string]int64{"0": 0, "1024": 1024, "500K": 500 << 10, "5M": 5 << 20, "1.5m": 3 << 19, "2MiB": 2 << 20, "1G/s": 1 << 30}
+	for text, expected := range cases {
+		if value, err := http.ParseBandwidth(text); value != expected || err != nil {
+			t.Errorf("Unexpected bandwidth for '%s': %d (%v)", text, value, err)
+		}
+	}
+	for _, text := range []string{"", "fast", "-5M", "5X"} {
+		if _, err := http.ParseBandwidth(text); err == nil {
+			t.Errorf("Expected an error for the bandwidth '%s'.", text)
+		}
+	}
+}
+
+func TestBandwidthLimit(t *testing.T) {
+	content := bytes.Repeat([]byte("x"), 96<<10)
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+	directory := t.TempDir()
+
+	// 96 KiB at 128 KiB/s, with an initial burst of 32 KiB, take at least half a second.
+	start := time.Now()
+	status, err := download.From(directory, "limited.bin", server.URL+"/limited.bin", http.WithBandwidthLimit(128<<10))
+	if err != nil || status.Bytes != int64(len(content)) {
+		t.Fatalf("Unexpected limited download: %+v (%v)", status, err)
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("Expected the download to be limited, it took %s", elapsed)
+	}
+
+	// Two concurrent downloads share the global limit, so they take as long as a single one with the whole content.
+	http.DefaultConfig.BandwidthLimit = 256 << 10
+	start = time.Now()
+	fns := make([]func() (download.Result, error), 0, 2)
+	for _, name := range []string{"first.bin", "second.bin"} {
+		fns = append(fns, func() (download.Result, error) {
+			return download.From(directory, name, server.URL+"/"+name)
+		})
+	}
+	if _, err := async.All(async.NewFuture(fns[0]), async.NewFuture(fns[1])).Get(); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+		t.Errorf("Expected the downloads to share the global limit, they took %s", elapsed)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const entryExtension = ".json"
+
+// Entry This struct represents a cached response's body and the validators used to revalidate it with conditional requests,
+// the Link header is also stored, so paginated responses served from the cache can still be followed.
+type Entry struct {
+	Url          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Link         string    `json:"link,omitempty"`
+	StoredAt     time.Time `json:"stored_at"`
+	Body         string    `json:"body"`
+}
+
+// Revalidable This method returns whether this entry has any validator that can be used for a conditional request.
+func (e *Entry) Revalidable() bool {
+	return e.ETag != "" || e.LastModified != ""
+}
+
+// Store This struct is an on-disk cache of responses, every entry is stored as a json-file into the store's directory.
+type Store struct {
+	directory string
+}
+
+// NewStore This function creates a new Store using the given directory, which is created when the first entry is saved.
+func NewStore(directory string) *Store {
+	return &Store{directory: directory}
+}
+
+// NewDefaultStore This function creates a new Store using the DefaultDirectory.
+func NewDefaultStore() (*Store, error) {
+	directory, err := DefaultDirectory()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(directory), nil
+}
+
+// DefaultDirectory This function returns the directory used by default for the responses' cache, which is placed under the
+// user's cache directory.
+func DefaultDirectory() (string, error) {
+	directory, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(directory, "gvw", "http"), nil
+}
+
+// Directory This method returns the store's directory.
+func (s *Store) Directory() string {
+	return s.directory
+}
+
+// Key This function returns the key used for the given url's entry, the identity (such as the used token) is part of the key
+// so responses obtained with different credentials are never shared, and it is not stored in plain-text.
+func Key(url string, identity string) string {
+	hash := sha256.Sum256([]byte(identity + "\n" + url))
+	return hex.EncodeToString(hash[:])
+}
+
+// Load This method returns the entry stored with the given key, or nil if there is no entry for it.
+func (s *Store) Load(key string) (*Entry, error) {
+	content, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entry Entry
+	if err := json.Unmarshal(content, &entry); err != nil {
+		// A corrupted entry is discarded so it is replaced by the next response.
+		_ = os.Remove(s.path(key))
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+// Save This method stores the given entry with the given key, replacing the previous one atomically.
+func (s *Store) Save(key string, entry *Entry) error {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.directory, 0o700); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(s.directory, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+	if _, err := file.Write(content); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), s.path(key))
+}
+
+// Entries This method returns all the stored entries.
+func (s *Store) Entries() ([]Entry, error) {
+	keys, err := s.keys()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(keys))
+	for _, key := range keys {
+		entry, err := s.Load(key)
+		if err != nil {
+			return nil, err
+		}
+		if entry != nil {
+			entries = append(entries, *entry)
+		}
+	}
+	return entries, nil
+}
+
+// Purge This method removes all the stored entries, and returns the amount of removed entries.
+func (s *Store) Purge() (int, error) {
+	keys, err := s.keys()
+	if err != nil {
+		return 0, err
+	}
+	for index, key := range keys {
+		if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return index, err
+		}
+	}
+	return len(keys), nil
+}
+
+func (s *Store) keys() ([]string, error) {
+	files, err := os.ReadDir(s.directory)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, file := range files {
+		if name := file.Name(); !file.IsDir() && strings.HasSuffix(name, entryExtension) {
+			keys = append(keys, strings.TrimSuffix(name, entryExtension))
+		}
+	}
+	return keys, nil
+}
+
+func (s *Store) path(key string) string {
+	return filepath.Join(s.directory, key+entryExtension)
+}
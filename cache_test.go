@@ -0,0 +1,75 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"viewer/main/cache"
+	"viewer/main/http"
+	"viewer/main/utils"
+)
+
+func TestResponseCacheRevalidation(t *testing.T) {
+	fullResponses := 0
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(nethttp.StatusNotModified)
+			return
+		}
+		fullResponses++
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"name":"repo-viewer"}`))
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+	store := cache.NewStore(t.TempDir())
+	http.DefaultConfig.Cache = store
+
+	for attempt := 0; attempt < 2; attempt++ {
+		resp, err := utils.Response(nethttp.DefaultClient, ForRepository("aivruu", "repo-viewer"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := resp.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.ResponseOkStatus || string(body) != `{"name":"repo-viewer"}` {
+			t.Fatalf("Unexpected response: status %d, body '%s'", resp.StatusCode, body)
+		}
+		if resp.Cached != (attempt == 1) {
+			t.Errorf("Unexpected cached flag '%t' for attempt %d.", resp.Cached, attempt)
+		}
+	}
+	if fullResponses != 1 {
+		t.Errorf("Expected a single full response, got %d.", fullResponses)
+	}
+	entries, err := store.Entries()
+	if err != nil || len(entries) != 1 || entries[0].ETag != `"v1"` {
+		t.Errorf("Unexpected cache entries: %+v (%v)", entries, err)
+	}
+	if removed, err := store.Purge(); err != nil || removed != 1 {
+		t.Errorf("Expected a single purged entry, got %d (%v)", removed, err)
+	}
+}
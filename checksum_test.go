@@ -0,0 +1,90 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"viewer/main/http"
+	"viewer/main/repository"
+)
+
+func TestChecksumVerification(t *testing.T) {
+	content := []byte("release-binary")
+	sum := sha256.Sum256(content)
+	digest := hex.EncodeToString(sum[:])
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		switch r.URL.Path {
+		case "/SHA256SUMS":
+			_, _ = w.Write([]byte(digest + "  app.bin\n" + digest + " *tampered.bin\n" + digest + "  broken.bin\n"))
+		case "/checksums.txt", "/single.bin.sha256":
+			// A digest without the asset's name only belongs to the asset-specific checksum assets.
+			_, _ = w.Write([]byte(digest + "\n"))
+		case "/broken.bin.sha256":
+			nethttp.NotFound(w, r)
+		case "/tampered.bin":
+			_, _ = w.Write([]byte("tampered-binary"))
+		default:
+			_, _ = w.Write(content)
+		}
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+	model := &repository.GithubReleaseModel{Assets: []repository.Asset{
+		{Name: "app.bin", Url: server.URL + "/app.bin"},
+		{Name: "tampered.bin", Url: server.URL + "/tampered.bin"},
+		{Name: "unknown.bin", Url: server.URL + "/unknown.bin"},
+		{Name: "digest.bin", Url: server.URL + "/digest.bin", Digest: "sha256:" + digest},
+		{Name: "SHA256SUMS", Url: server.URL + "/SHA256SUMS"},
+		{Name: "listed.bin", Url: server.URL + "/listed.bin"},
+		{Name: "single.bin", Url: server.URL + "/single.bin"},
+		{Name: "broken.bin", Url: server.URL + "/broken.bin"},
+		{Name: "checksums.txt", Url: server.URL + "/checksums.txt"},
+		{Name: "single.bin.sha256", Url: server.URL + "/single.bin.sha256"},
+		{Name: "broken.bin.sha256", Url: server.URL + "/broken.bin.sha256"},
+	}}
+	directory := t.TempDir()
+
+	if checksum, err := model.Checksum(0); err != nil || checksum != "sha256:"+digest {
+		t.Errorf("Unexpected checksum '%s' (%v)", checksum, err)
+	}
+	for index, expected := range []error{nil, http.ErrChecksumMismatch, http.ErrNoChecksum, nil, nil, http.ErrNoChecksum, nil, nil} {
+		_, err := model.Download(directory, index, http.WithVerify(true))
+		if !errors.Is(err, expected) {
+			t.Errorf("Expected '%v' for '%s', got '%v'.", expected, model.Assets[index].Name, err)
+		}
+		_, statErr := os.Stat(filepath.Join(directory, model.Assets[index].Name))
+		if (expected == nil) != (statErr == nil) {
+			t.Errorf("Unexpected file state for '%s': %v", model.Assets[index].Name, statErr)
+		}
+	}
+	var checksumErr *http.ChecksumError
+	if _, err := model.Download(directory, 0, http.WithChecksum("sha256:"+hex.EncodeToString(make([]byte, 32)))); !errors.As(err, &checksumErr) {
+		t.Errorf("Expected a checksum error for the explicit checksum, got '%v'.", err)
+	}
+}
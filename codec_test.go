@@ -0,0 +1,88 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"viewer/main/http"
+	"viewer/main/repository"
+	"viewer/main/repository/codec"
+)
+
+type licenseModel struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+// upperCaseCodecProvider A codec.Provider that decodes licenseModel objects with the name in upper-case.
+type upperCaseCodecProvider struct{}
+
+func (p *upperCaseCodecProvider) Decode(reader io.Reader) (*licenseModel, error) {
+	model, err := codec.DecodeJSON[licenseModel](reader, true)
+	if err != nil {
+		return nil, err
+	}
+	model.Name = strings.ToUpper(model.Name)
+	return model, nil
+}
+
+func TestStrictDecoding(t *testing.T) {
+	provider := codec.NewJSONProvider[repository.GithubTagModel](true)
+	if model, err := provider.Decode(strings.NewReader(` {"name":"v1.0","commit":{"sha":"abc"}}`)); err != nil || model.Commit.Sha != "abc" {
+		t.Errorf("Expected the payload to be decoded, got '%v'.", err)
+	}
+	for _, payload := range []string{`[{"name":"v1.0"}]`, `null`, `{"name":"v1.0","zipball_url":""}`, `{"name":"v1.0"} {}`, ``} {
+		if _, err := provider.Decode(strings.NewReader(payload)); !errors.Is(err, codec.ErrUnexpectedPayload) {
+			t.Errorf("Expected the payload '%s' to be rejected, got '%v'.", payload, err)
+		}
+	}
+	lenient := codec.NewJSONProvider[repository.GithubTagModel](false)
+	if _, err := lenient.Decode(strings.NewReader(`{"name":"v1.0","zipball_url":""}`)); err != nil {
+		t.Errorf("Expected the unknown fields to be ignored, got '%v'.", err)
+	}
+}
+
+func TestRegisteredCodec(t *testing.T) {
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		_, _ = w.Write([]byte(`{"key":"mit","name":"MIT License"}`))
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+	codec.Register[licenseModel](&upperCaseCodecProvider{})
+
+	consumed := false
+	model, err := http.RequestAndThen(repository.NewRequest[licenseModel](server.URL+"/licenses/mit"), func(*licenseModel) {
+		consumed = true
+	}, 5*time.Second)
+	if err != nil || model.Name != "MIT LICENSE" {
+		t.Fatalf("Expected the registered codec to be used, got '%v' (%v).", model, err)
+	}
+	if !consumed {
+		t.Error("Expected the consumer to be executed for the decoded model.")
+	}
+}
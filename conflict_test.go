@@ -0,0 +1,89 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"viewer/main/download"
+	"viewer/main/http"
+)
+
+func TestDownloadConflictPolicies(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		requests++
+		if r.URL.Path == "/missing.tar.gz" {
+			nethttp.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("new-content"))
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+	directory := t.TempDir()
+	path := filepath.Join(directory, "app.tar.gz")
+	writeExisting := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	readFile := func(path string) string {
+		content, _ := os.ReadFile(path)
+		return string(content)
+	}
+
+	writeExisting("old-content")
+	if _, err := download.From(directory, "app.tar.gz", server.URL+"/app.tar.gz", http.WithConflictPolicy(http.ConflictFail)); !errors.Is(err, http.ErrFileExists) {
+		t.Errorf("Expected the existing file error, got '%v'.", err)
+	}
+	if _, err := download.From(directory, "missing.tar.gz", server.URL+"/missing.tar.gz"); !errors.Is(err, http.ErrNotFound) {
+		t.Errorf("Expected the not found error, got '%v'.", err)
+	}
+	if _, err := os.Stat(filepath.Join(directory, "missing.tar.gz")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected no file for the failed download, got '%v'.", err)
+	}
+
+	status, err := download.From(directory, "app.tar.gz", server.URL+"/app.tar.gz", http.WithConflictPolicy(http.ConflictRename))
+	if err != nil || status.Path != filepath.Join(directory, "app-1.tar.gz") || readFile(status.Path) != "new-content" {
+		t.Errorf("Unexpected renamed download: %+v (%v)", status, err)
+	}
+	if readFile(path) != "old-content" {
+		t.Error("Expected the existing file to be kept.")
+	}
+
+	writeExisting("new-content")
+	requests = 0
+	status, err = download.From(directory, "app.tar.gz", server.URL+"/app.tar.gz", http.WithConflictPolicy(http.ConflictSkipIdentical))
+	if err != nil || !status.Skipped() || requests != 1 {
+		t.Errorf("Expected the identical file to be skipped: %+v, %d requests (%v)", status, requests, err)
+	}
+	writeExisting("other-content-size")
+	status, err = download.From(directory, "app.tar.gz", server.URL+"/app.tar.gz", http.WithConflictPolicy(http.ConflictSkipIdentical))
+	if err != nil || !status.Downloaded() || readFile(path) != "new-content" {
+		t.Errorf("Expected the different file to be replaced: %+v (%v)", status, err)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"viewer/main/download"
+	"viewer/main/utils"
+)
+
+func TestDownloadCancellation(t *testing.T) {
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		// Send a chunk, and then keep the download in progress until the client goes away.
+		_, _ = w.Write([]byte("partial-content"))
+		w.(nethttp.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	directory := t.TempDir()
+	status, err := download.FromContext(ctx, directory, "asset.bin", server.URL+"/asset")
+	if !status.Error() || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the cancelled download to fail, status '%d' (%v).", status.Status, err)
+	}
+	if _, err := os.Stat(filepath.Join(directory, "asset.bin")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the partial file to be removed, got '%v'.", err)
+	}
+	if _, err := utils.ResponseContext(ctx, nethttp.DefaultClient, server.URL); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the context's error for a request with a done context, got '%v'.", err)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package download
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+	vhttp "viewer/main/http"
+)
+
+// checksumVerifier This struct computes a download's SHA-256 checksum while its content is written, to provide it in the
+// download's Result and to verify it against the expected one, if any. A nil checksumVerifier is valid and doesn't compute
+// anything.
+type checksumVerifier struct {
+	name     string
+	expected string
+	hash     hash.Hash
+}
+
+// newChecksumVerifier This function creates a new checksumVerifier for the given file-name's download using the given
+// checksum, which looks like "sha256:<hex-digest>", or is just the SHA-256 hex-digest. If the given checksum is empty,
+// the content is hashed without verifying it, and an error is returned if its algorithm is not supported.
+func newChecksumVerifier(name string, checksum string) (*checksumVerifier, error) {
+	if checksum == "" {
+		return &checksumVerifier{name: name, hash: sha256.New()}, nil
+	}
+	algorithm, digest, found := strings.Cut(checksum, ":")
+	if !found {
+		algorithm, digest = "sha256", checksum
+	}
+	if !strings.EqualFold(algorithm, "sha256") {
+		return nil, fmt.Errorf("unsupported checksum algorithm '%s'", algorithm)
+	}
+	if decoded, err := hex.DecodeString(digest); err != nil || len(decoded) != sha256.Size {
+		return nil, fmt.Errorf("malformed sha256 checksum '%s'", digest)
+	}
+	return &checksumVerifier{name: name, expected: strings.ToLower(digest), hash: sha256.New()}, nil
+}
+
+// start This method resets the computed checksum, and hashes the first given amount of bytes of the given file, which
+// were written by a previous download.
+func (v *checksumVerifier) start(path string, offset int64) error {
+	if v == nil {
+		return nil
+	}
+	v.hash.Reset()
+	if offset == 0 {
+		return nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%w: %w", vhttp.ErrFileSystem, err)
+	}
+	defer func(File *os.File) {
+		_ = File.Close()
+	}(file)
+	if _, err := io.CopyN(v.hash, file, offset); err != nil {
+		return fmt.Errorf("%w: %w", vhttp.ErrFileSystem, err)
+	}
+	return nil
+}
+
+// writer This method returns a writer that writes into the given writer while the content is hashed.
+func (v *checksumVerifier) writer(writer io.Writer) io.Writer {
+	if v == nil {
+		return writer
+	}
+	return io.MultiWriter(writer, v.hash)
+}
+
+// expects This method returns whether there is an expected checksum to verify the content.
+func (v *checksumVerifier) expects() bool {
+	return v != nil && v.expected != ""
+}
+
+// sum This method returns the computed SHA-256 hex-digest.
+func (v *checksumVerifier) sum() string {
+	if v == nil {
+		return ""
+	}
+	return hex.EncodeToString(v.hash.Sum(nil))
+}
+
+// verify This method returns a vhttp.ChecksumError if the computed checksum doesn't match the expected one, if any.
+func (v *checksumVerifier) verify() error {
+	if !v.expects() {
+		return nil
+	}
+	if actual := v.sum(); actual != v.expected {
+		return &vhttp.ChecksumError{Name: v.name, Expected: "sha256:" + v.expected, Actual: "sha256:" + actual}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	vhttp "viewer/main/http"
+	"viewer/main/utils"
+)
+
+// compoundExtensions The extensions composed by multiple ones, which are kept together when a suffix is added to a file-name.
+var compoundExtensions = []string{".tar.gz", ".tar.bz2", ".tar.xz", ".tar.zst"}
+
+// CommitPart This function moves the given path's part-file to its final path according to the given vhttp.ConflictPolicy,
+// returning the final path, which has a numeric suffix when the vhttp.ConflictRename policy is used and the file exists.
+// The vhttp.ConflictSkipIdentical policy replaces the file, as its content is expected to be compared before. It can be
+// used to commit the part-files written by other means, such as ToWriter.
+func CommitPart(path string, policy vhttp.ConflictPolicy) (string, error) {
+	partPath := path + PartSuffix
+	switch policy {
+	case vhttp.ConflictFail:
+		if err := linkPart(partPath, path); err != nil {
+			if errors.Is(err, os.ErrExist) {
+				return "", fmt.Errorf("%w: '%s'", vhttp.ErrFileExists, path)
+			}
+			return "", fmt.Errorf("%w: %w", vhttp.ErrFileSystem, err)
+		}
+		return path, nil
+	case vhttp.ConflictRename:
+		for attempt := 0; ; attempt++ {
+			target := suffixedPath(path, attempt)
+			err := linkPart(partPath, target)
+			if err == nil {
+				return target, nil
+			}
+			if !errors.Is(err, os.ErrExist) {
+				return "", fmt.Errorf("%w: %w", vhttp.ErrFileSystem, err)
+			}
+		}
+	default:
+		if err := os.Rename(partPath, path); err != nil {
+			return "", fmt.Errorf("%w: %w", vhttp.ErrFileSystem, err)
+		}
+		return path, nil
+	}
+}
+
+// linkPart This function moves the given part-file to the given target path only if it doesn't exist, returning an error
+// matching with os.ErrExist otherwise. A hard-link is used to avoid replacing a file created concurrently, or a checked
+// rename if the file-system doesn't support them.
+func linkPart(partPath string, target string) error {
+	err := os.Link(partPath, target)
+	if err == nil {
+		return os.Remove(partPath)
+	}
+	if errors.Is(err, os.ErrExist) {
+		return err
+	}
+	if _, statErr := os.Lstat(target); statErr == nil {
+		return os.ErrExist
+	}
+	return os.Rename(partPath, target)
+}
+
+// suffixedPath This function returns the given path with the given numeric suffix before its extension, such as
+// "app-1.tar.gz", or the path itself for the zero suffix.
+func suffixedPath(path string, suffix int) string {
+	if suffix == 0 {
+		return path
+	}
+	extension := filepath.Ext(path)
+	for _, compound := range compoundExtensions {
+		if strings.HasSuffix(strings.ToLower(path), compound) {
+			extension = path[len(path)-len(compound):]
+			break
+		}
+	}
+	return strings.TrimSuffix(path, extension) + "-" + strconv.Itoa(suffix) + extension
+}
+
+// identicalFile This function returns whether the existing file in the given path, with the given size, is identical to
+// the given url's content, which is compared using the given checksumVerifier if it expects a checksum, or the content's
+// size otherwise.
+func identicalFile(ctx context.Context, path string, size int64, url string, verifier *checksumVerifier, options ...vhttp.RequestOption) (bool, error) {
+	if verifier.expects() {
+		if err := verifier.start(path, size); err != nil {
+			return false, err
+		}
+		return verifier.verify() == nil, nil
+	}
+	resp, err := utils.OriginalResponseContext(ctx, url, slices.Concat(options, []vhttp.RequestOption{
+		vhttp.WithHeader("Range", "bytes=0-0"),
+	})...)
+	if err != nil {
+		return false, err
+	}
+	_ = resp.Body.Close()
+	remoteSize := resp.ContentLength
+	if resp.StatusCode == http.StatusPartialContent {
+		if _, total, err := contentRange(resp.Header.Get("Content-Range")); err == nil {
+			remoteSize = total
+		} else {
+			remoteSize = -1
+		}
+	}
+	return remoteSize >= 0 && remoteSize == size, nil
+}
@@ -22,52 +22,190 @@
 package download
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
-	"strings"
+	"slices"
+	"time"
+	vhttp "viewer/main/http"
 	"viewer/main/utils"
 )
 
+// validGithubUrl This function returns whether the given url belongs to the GitHub instance configured by the
+// http.DefaultConfig.
 func validGithubUrl(url string) bool {
-	return strings.HasPrefix(url, "https://github.com/") || strings.HasPrefix(url, "https://api.github.com/")
+	return vhttp.DefaultConfig.ValidDownloadUrl(url)
 }
 
-// From This function downloads the content from the given url into the specified file-name, and returns a DownloadStatusProvider.
-func From(directory string, fileName string, url string) DownloadingStatusProvider {
+// From This function downloads the content from the given url into the specified file-name through a part-file (the
+// file-name with the PartSuffix), which is committed once it's complete and verified, and resumed if it was interrupted
+// and the content didn't change. It returns the download's Result and error, which can be inspected with errors.Is against
+// the http package's errors, such as vhttp.ErrInvalidAssetUrl or vhttp.ErrNotFound.
+func From(directory string, fileName string, url string, options ...vhttp.RequestOption) (Result, error) {
+	return FromContext(context.Background(), directory, fileName, url, options...)
+}
+
+// FromContext This function realizes the same execution that From, the download is aborted when the given context is done.
+// The part-file of a failed download is kept to resume it later if the content provides an ETag or a modification date,
+// otherwise it is removed.
+func FromContext(ctx context.Context, directory string, fileName string, url string, options ...vhttp.RequestOption) (Result, error) {
+	start := time.Now()
 	if !validGithubUrl(url) {
-		return WithInvalidUrl()
+		err := fmt.Errorf("%w: '%s' doesn't belong to '%s'", vhttp.ErrInvalidAssetUrl, url, vhttp.DefaultConfig.BaseUrl())
+		return Result{Status: InvalidAssetUrlStatus}.finish(start, err)
 	}
-	file, err := os.Create(filepath.Join(directory, fileName))
+	path := filepath.Join(directory, fileName)
+	requestOptions := vhttp.DefaultConfig.Options(options...)
+	verifier, err := newChecksumVerifier(fileName, requestOptions.Checksum)
 	if err != nil {
-		fmt.Println("Error during file creation: ", err)
-		return WithDownloadError()
+		return Result{}.finish(start, err)
 	}
-	// [os.File] object closing and error handling.
-	defer func(File *os.File) {
-		if err := File.Close(); err != nil {
-			fmt.Println("Error during File closing: ", err)
+	if stat, err := os.Stat(path); err == nil {
+		switch requestOptions.ConflictPolicy {
+		case vhttp.ConflictFail:
+			return Result{}.finish(start, fmt.Errorf("%w: '%s'", vhttp.ErrFileExists, path))
+		case vhttp.ConflictSkipIdentical:
+			identical, err := identicalFile(ctx, path, stat.Size(), url, verifier, options...)
+			if err != nil {
+				return Result{}.finish(start, err)
+			}
+			if identical {
+				// The existing file was hashed if it was compared with the checksum, otherwise it is hashed now.
+				if !verifier.expects() {
+					if err := verifier.start(path, stat.Size()); err != nil {
+						return Result{}.finish(start, err)
+					}
+				}
+				// The existing file is kept even if it's not valid, as it wasn't written by this download.
+				if err := validate(path, options...); err != nil {
+					return Result{}.finish(start, err)
+				}
+				result := Result{Status: AssetSkippedStatus, Path: path, Bytes: stat.Size(), Sha256: verifier.sum()}
+				return result.finish(start, nil)
+			}
 		}
-	}(file)
-	// Make request to the given url and get the [Response] object.
-	request := utils.OriginalResponse(url)
-	resp := request.Get()
-	if resp == nil {
-		return WithDownloadError()
 	}
-	size, err := io.Copy(file, resp.Body)
+	offset, info := resumeOffset(path, url)
+	if requestOptions.Segments > 1 && offset == 0 {
+		if result, ok, err := fromSegments(ctx, path, url, requestOptions.Segments, verifier, options...); ok {
+			return result.finish(start, err)
+		}
+	}
+	result, err := fromStream(ctx, path, url, offset, info, verifier, options...)
+	return result.finish(start, err)
+}
+
+// fromStream This function downloads the content from the given url into the given path's part-file using a single
+// connection, resuming it from the given offset when it's positive, and hashing it with the given checksumVerifier, as
+// described by From.
+func fromStream(ctx context.Context, path string, url string, offset int64, info *partInfo, verifier *checksumVerifier, options ...vhttp.RequestOption) (Result, error) {
+	requestOptions := options
+	if offset > 0 {
+		requestOptions = append(slices.Clone(options),
+			vhttp.WithHeader("Range", fmt.Sprintf("bytes=%d-", offset)),
+			vhttp.WithHeader("If-Range", info.validator()))
+	}
+	// Make request to the given url and get the [Response] object.
+	resp, err := utils.OriginalResponseContext(ctx, url, requestOptions...)
 	if err != nil {
-		fmt.Println("Error body's information copying into file: ", err)
-		return WithDownloadError()
+		var statusErr *vhttp.StatusError
+		if offset > 0 && errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			// The part-file doesn't match the remote content anymore, so it is downloaded again.
+			removePart(path)
+			return fromStream(ctx, path, url, 0, nil, verifier, options...)
+		}
+		return Result{}, err
 	}
 	defer func(Body io.ReadCloser) {
-		if err := Body.Close(); err != nil {
-			fmt.Println("Error during body closing: ", err)
-		}
+		_ = Body.Close()
 	}(resp.Body)
-	if size == 0 {
-		return WithUnknownAsset()
+	size := resp.ContentLength
+	if resp.StatusCode == http.StatusPartialContent && offset > 0 {
+		start, total, err := contentRange(resp.Header.Get("Content-Range"))
+		if err != nil || start != offset || (info.Size >= 0 && total >= 0 && total != info.Size) {
+			// The provided range can't be appended to the part-file, so it is downloaded again.
+			removePart(path)
+			_ = resp.Body.Close()
+			return fromStream(ctx, path, url, 0, nil, verifier, options...)
+		}
+		size = total
+	} else {
+		// The server provided the complete content, as it changed or doesn't support ranges.
+		offset = 0
+	}
+	result := Result{HttpStatus: resp.StatusCode, ContentType: resp.Header.Get("Content-Type"), Resumed: offset > 0}
+	info = partInfoFrom(url, resp.Header, size)
+	if info.resumable() {
+		_ = info.save(path)
+	} else {
+		removePart(path)
+	}
+	tracker := newProgressTracker(vhttp.DefaultConfig.Options(options...).Progress, filepath.Base(path), offset, info.Size)
+	defer tracker.finish()
+	throttle := newThrottle(vhttp.DefaultConfig.Options(options...).BandwidthLimit)
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if offset > 0 {
+		flags = os.O_WRONLY | os.O_APPEND
+	}
+	file, err := os.OpenFile(path+PartSuffix, flags, 0o644)
+	if err != nil {
+		return result, fmt.Errorf("%w: %w", vhttp.ErrFileSystem, err)
+	}
+	if err := verifier.start(path+PartSuffix, offset); err != nil {
+		_ = file.Close()
+		removePart(path)
+		return result, err
+	}
+	completed := false
+	// [os.File] object closing, the part-file is removed if the download wasn't completed and can't be resumed.
+	defer func(File *os.File) {
+		_ = File.Close()
+		if !completed && !info.resumable() {
+			removePart(path)
+		}
+	}(file)
+	read, err := io.Copy(verifier.writer(file), tracker.reader(throttle.reader(ctx, resp.Body)))
+	result.Bytes = offset + read
+	if err != nil {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		return result, fmt.Errorf("%w: copying '%s' into '%s': %w", vhttp.ErrNetwork, url, path, err)
+	}
+	if info.Size >= 0 && offset+read != info.Size {
+		return result, fmt.Errorf("%w: '%s' provided %d of %d bytes", vhttp.ErrNetwork, url, offset+read, info.Size)
+	}
+	if err := file.Close(); err != nil {
+		return result, fmt.Errorf("%w: %w", vhttp.ErrFileSystem, err)
+	}
+	if err := verifier.verify(); err != nil {
+		// The content is not the expected one, so it can't be resumed either.
+		removePart(path)
+		return result, err
+	}
+	if err := validate(path+PartSuffix, options...); err != nil {
+		removePart(path)
+		return result, err
+	}
+	finalPath, err := CommitPart(path, vhttp.DefaultConfig.Options(options...).ConflictPolicy)
+	if err != nil {
+		return result, err
+	}
+	completed = true
+	removePart(path)
+	result.Path, result.Sha256 = finalPath, verifier.sum()
+	return result, nil
+}
+
+// validate This function checks the given file with the validator specified by the given options, if any.
+func validate(path string, options ...vhttp.RequestOption) error {
+	validator := vhttp.DefaultConfig.Options(options...).Validator
+	if validator == nil {
+		return nil
 	}
-	return WithAssetDownload(size)
+	return validator(path)
 }
@@ -21,61 +21,103 @@
 
 package download
 
+import (
+	"errors"
+	"time"
+	vhttp "viewer/main/http"
+)
+
+// Status This type correspond to the outcome (byte-value) of a download.
+type Status byte
+
 const (
-	AssetDownloadedStatus    = byte(0)   // Asset was downloaded.
-	UnknownAssetStatus       = byte(1)   // The asset wasn't downloaded, may be unknown.
-	InvalidAssetUrlStatus    = byte(2)   // The asset's URL is not valid.
-	AssetDownloadErrorStatus = byte(3)   // The asset couldn't be downloaded.
-	UnknownAssetDefaultSize  = int64(0)  // Used for non-downloaded (zero read bytes) assets.
-	InvalidAssetDefaultSize  = int64(-1) // Used for failed-downloaded assets.
+	AssetDownloadedStatus    Status = iota // Asset was downloaded.
+	UnknownAssetStatus                     // The asset was downloaded, but its content is empty.
+	InvalidAssetUrlStatus                  // The asset's URL is not valid.
+	AssetDownloadErrorStatus               // The asset couldn't be downloaded.
+	AssetSkippedStatus                     // The asset wasn't downloaded, as an identical file exists.
 )
 
-// DownloadingStatusProvider This struct is used as status-provider for the repositories' assets' downloads.
-type DownloadingStatusProvider struct {
-	Status byte  // The response's code.
-	Result int64 // The amount of bytes read from the downloaded file.
+// statusNames The descriptions of the Status values.
+var statusNames = map[Status]string{
+	AssetDownloadedStatus:    "downloaded",
+	UnknownAssetStatus:       "empty",
+	InvalidAssetUrlStatus:    "invalid url",
+	AssetDownloadErrorStatus: "failed",
+	AssetSkippedStatus:       "skipped",
 }
 
-// WithAssetDownload This method creates a new DownloadingStatusProvider using the given amount of read-bytes, and the
-// AssetDownloadedStatus status.
-func WithAssetDownload(result int64) DownloadingStatusProvider {
-	return DownloadingStatusProvider{Status: AssetDownloadedStatus, Result: result}
+func (s Status) String() string {
+	return statusNames[s]
 }
 
-// WithUnknownAsset This method creates a new DownloadingStatusProvider using the UnknownAssetDefaultSize for result-value,
-// and providing the UnknownAssetStatus status.
-func WithUnknownAsset() DownloadingStatusProvider {
-	return DownloadingStatusProvider{Status: UnknownAssetStatus, Result: UnknownAssetDefaultSize}
+// Result This struct describes the outcome of a repository's asset's download, as returned by From and ToWriter.
+type Result struct {
+	Status      Status        // The download's outcome.
+	Path        string        // The downloaded (or skipped) file's path, which may differ from the requested one due to conflicts.
+	Bytes       int64         // The content's written bytes, including the ones downloaded before it was resumed.
+	Sha256      string        // The content's SHA-256 hex-digest, empty if the download failed.
+	HttpStatus  int           // The status-code of the content's response, zero if the content wasn't requested.
+	ContentType string        // The content's type provided by the server.
+	Duration    time.Duration // The time spent by the download, including its verification.
+	Resumed     bool          // Whether the download continued the part-file of a previous one.
+	Err         error         // The download's failure cause, nil if it was successful.
 }
 
-// WithInvalidUrl This method creates a new DownloadingStatusProvider using the InvalidAssetDefaultSize for result-value,
-// and using the InvalidAssetUrlStatus status.
-func WithInvalidUrl() DownloadingStatusProvider {
-	return DownloadingStatusProvider{Status: InvalidAssetUrlStatus, Result: InvalidAssetDefaultSize}
+// WithError This method returns a copy of this Result for a download failed with the given error, using the
+// AssetDownloadErrorStatus status unless the InvalidAssetUrlStatus status is used, and the status-code of the
+// unsuccessful response if the error provides it. The SHA-256 hex-digest is removed, as it doesn't belong to a verified
+// content.
+func (r Result) WithError(err error) Result {
+	if r.Status != InvalidAssetUrlStatus {
+		r.Status = AssetDownloadErrorStatus
+	}
+	var statusErr *vhttp.StatusError
+	var limitErr *vhttp.RateLimitError
+	if errors.As(err, &statusErr) {
+		r.HttpStatus = statusErr.StatusCode
+	} else if errors.As(err, &limitErr) {
+		r.HttpStatus = limitErr.StatusCode
+	}
+	r.Sha256 = ""
+	r.Err = err
+	return r
 }
 
-// WithDownloadError This method creates a new DownloadingStatusProvider using the InvalidAssetDefaultSize for result-value,
-// and using the AssetDownloadErrorStatus status.
-func WithDownloadError() DownloadingStatusProvider {
-	return DownloadingStatusProvider{Status: AssetDownloadErrorStatus, Result: InvalidAssetDefaultSize}
+// finish This method returns a copy of this Result for a download started at the given time and completed with the
+// given error, if any, using the UnknownAssetStatus status for the downloaded empty contents.
+func (r Result) finish(start time.Time, err error) (Result, error) {
+	r.Duration = time.Since(start)
+	if err != nil {
+		return r.WithError(err), err
+	}
+	if r.Status == AssetDownloadedStatus && r.Bytes == 0 {
+		r.Status = UnknownAssetStatus
+	}
+	return r, nil
 }
 
 // Downloaded This method return whether the status-code is AssetDownloadedStatus.
-func (d *DownloadingStatusProvider) Downloaded() bool {
-	return d.Status == AssetDownloadedStatus
+func (r *Result) Downloaded() bool {
+	return r.Status == AssetDownloadedStatus
 }
 
 // Unknown This method return whether the status-code is UnknownAssetStatus.
-func (d *DownloadingStatusProvider) Unknown() bool {
-	return d.Status == UnknownAssetStatus
+func (r *Result) Unknown() bool {
+	return r.Status == UnknownAssetStatus
 }
 
 // InvalidUrl This method return whether the status-code is InvalidAssetUrlStatus.
-func (d *DownloadingStatusProvider) InvalidUrl() bool {
-	return d.Status == InvalidAssetUrlStatus
+func (r *Result) InvalidUrl() bool {
+	return r.Status == InvalidAssetUrlStatus
+}
+
+// Skipped This method return whether the status-code is AssetSkippedStatus.
+func (r *Result) Skipped() bool {
+	return r.Status == AssetSkippedStatus
 }
 
 // Error This method return whether the status-code is AssetDownloadErrorStatus.
-func (d *DownloadingStatusProvider) Error() bool {
-	return d.Status == AssetDownloadErrorStatus
+func (r *Result) Error() bool {
+	return r.Status == AssetDownloadErrorStatus
 }
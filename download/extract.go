@@ -0,0 +1,385 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package download
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	vhttp "viewer/main/http"
+)
+
+// ArchiveFormat This type represents the archives' formats supported by Extract.
+type ArchiveFormat string
+
+const (
+	ZipFormat      ArchiveFormat = "zip"
+	TarFormat      ArchiveFormat = "tar"
+	TarGzipFormat  ArchiveFormat = "tar.gz"
+	TarBzip2Format ArchiveFormat = "tar.bz2"
+)
+
+// archiveSuffixes The file-names' suffixes of each ArchiveFormat.
+var archiveSuffixes = []struct {
+	suffix string
+	format ArchiveFormat
+}{
+	{".zip", ZipFormat},
+	{".tar", TarFormat},
+	{".tar.gz", TarGzipFormat},
+	{".tgz", TarGzipFormat},
+	{".tar.bz2", TarBzip2Format},
+	{".tbz2", TarBzip2Format},
+	{".tbz", TarBzip2Format},
+}
+
+// ExtractOptions This struct holds the settings used to extract an archive.
+type ExtractOptions struct {
+	StripComponents int      // The amount of leading path's components removed from the entries' names.
+	Include         []string // The path.Match patterns of the extracted entries (or their directories), all if it's empty.
+}
+
+// FormatOf This function returns the ArchiveFormat of the given file-name, and whether it is a supported archive.
+func FormatOf(name string) (ArchiveFormat, bool) {
+	lowerName := strings.ToLower(name)
+	for _, candidate := range archiveSuffixes {
+		if strings.HasSuffix(lowerName, candidate.suffix) {
+			return candidate.format, true
+		}
+	}
+	return "", false
+}
+
+// Extract This function extracts the given archive into the specified directory using the given ExtractOptions, and
+// returns the extracted files' paths. The archive's format is taken from its name, returning an error matching with
+// vhttp.ErrUnsupportedArchive if it's not supported. Entries whose paths escape the directory, and symbolic or hard links
+// pointing outside of it, are rejected with vhttp.ErrUnsafeArchive, and no entry is written through a link.
+func Extract(archive string, directory string, options ExtractOptions) ([]string, error) {
+	format, found := FormatOf(archive)
+	if !found {
+		return nil, fmt.Errorf("%w: '%s'", vhttp.ErrUnsupportedArchive, filepath.Base(archive))
+	}
+	e := &extractor{directory: directory, options: options}
+	var err error
+	if format == ZipFormat {
+		err = e.extractZip(archive)
+	} else {
+		err = e.extractTar(archive, format)
+	}
+	return e.extracted, err
+}
+
+// extractor This struct writes the entries of an archive into a directory.
+type extractor struct {
+	directory string
+	options   ExtractOptions
+	extracted []string
+}
+
+func (e *extractor) extractZip(archive string) error {
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return fmt.Errorf("%w: %w", vhttp.ErrUnsupportedArchive, err)
+	}
+	defer func(reader *zip.ReadCloser) {
+		_ = reader.Close()
+	}(reader)
+	for _, file := range reader.File {
+		if err := e.extractZipEntry(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *extractor) extractZipEntry(file *zip.File) error {
+	content, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("reading '%s': %w", file.Name, err)
+	}
+	defer func(content io.ReadCloser) {
+		_ = content.Close()
+	}(content)
+	mode := file.Mode()
+	switch {
+	case mode.IsDir():
+		return e.directoryEntry(file.Name)
+	case mode&fs.ModeSymlink != 0:
+		target, err := io.ReadAll(io.LimitReader(content, 4096))
+		if err != nil {
+			return fmt.Errorf("reading '%s': %w", file.Name, err)
+		}
+		return e.symlinkEntry(file.Name, string(target))
+	case mode.IsRegular():
+		return e.fileEntry(file.Name, mode, content)
+	default:
+		return nil
+	}
+}
+
+func (e *extractor) extractTar(archive string, format ArchiveFormat) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return fmt.Errorf("%w: %w", vhttp.ErrFileSystem, err)
+	}
+	defer func(File *os.File) {
+		_ = File.Close()
+	}(file)
+	var reader io.Reader = file
+	switch format {
+	case TarGzipFormat:
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("%w: %w", vhttp.ErrUnsupportedArchive, err)
+		}
+		defer func(gzipReader *gzip.Reader) {
+			_ = gzipReader.Close()
+		}(gzipReader)
+		reader = gzipReader
+	case TarBzip2Format:
+		reader = bzip2.NewReader(file)
+	}
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %w", vhttp.ErrUnsupportedArchive, err)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = e.directoryEntry(header.Name)
+		case tar.TypeReg:
+			err = e.fileEntry(header.Name, header.FileInfo().Mode(), tarReader)
+		case tar.TypeSymlink:
+			err = e.symlinkEntry(header.Name, header.Linkname)
+		case tar.TypeLink:
+			err = e.hardLinkEntry(header.Name, header.Linkname)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// entryPath This method returns the given entry's path relative to the directory after the ExtractOptions'
+// StripComponents are removed, and its full path. The returned boolean is false when the entry must be skipped.
+func (e *extractor) entryPath(name string) (string, string, bool, error) {
+	components := strings.Split(strings.Trim(strings.ReplaceAll(name, "\\", "/"), "/"), "/")
+	if len(components) <= e.options.StripComponents {
+		return "", "", false, nil
+	}
+	relative := path.Join(components[e.options.StripComponents:]...)
+	if strings.HasPrefix(name, "/") || !filepath.IsLocal(filepath.FromSlash(relative)) {
+		return "", "", false, fmt.Errorf("%w: '%s' escapes the directory", vhttp.ErrUnsafeArchive, name)
+	}
+	if relative == "." || !e.included(relative) {
+		return "", "", false, nil
+	}
+	full := filepath.Join(e.directory, filepath.FromSlash(relative))
+	if err := e.checkParents(relative); err != nil {
+		return "", "", false, err
+	}
+	return relative, full, true, nil
+}
+
+// included This method returns whether the given relative path, or any of its directories, matches with the
+// ExtractOptions' Include patterns.
+func (e *extractor) included(relative string) bool {
+	if len(e.options.Include) == 0 {
+		return true
+	}
+	components := strings.Split(relative, "/")
+	for _, pattern := range e.options.Include {
+		for index := range components {
+			if matched, _ := path.Match(pattern, path.Join(components[:index+1]...)); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkParents This method returns an error if any of the given relative path's directories is a symbolic link, so no
+// entry is written through a link created by a previous entry.
+func (e *extractor) checkParents(relative string) error {
+	current := e.directory
+	components := strings.Split(relative, "/")
+	for _, component := range components[:len(components)-1] {
+		current = filepath.Join(current, component)
+		stat, err := os.Lstat(current)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %w", vhttp.ErrFileSystem, err)
+		}
+		if stat.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("%w: '%s' is written through a link", vhttp.ErrUnsafeArchive, relative)
+		}
+	}
+	return nil
+}
+
+// prepare This method creates the given full path's directories, and removes an existing link in the path, so it's not
+// followed when the entry is written.
+func prepare(full string) error {
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		return fmt.Errorf("%w: %w", vhttp.ErrFileSystem, err)
+	}
+	if stat, err := os.Lstat(full); err == nil && stat.Mode()&fs.ModeSymlink != 0 {
+		if err := os.Remove(full); err != nil {
+			return fmt.Errorf("%w: %w", vhttp.ErrFileSystem, err)
+		}
+	}
+	return nil
+}
+
+func (e *extractor) directoryEntry(name string) error {
+	_, full, ok, err := e.entryPath(name)
+	if !ok {
+		return err
+	}
+	if err := prepare(full); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(full, 0o755); err != nil {
+		return fmt.Errorf("%w: %w", vhttp.ErrFileSystem, err)
+	}
+	return nil
+}
+
+func (e *extractor) fileEntry(name string, mode fs.FileMode, content io.Reader) error {
+	_, full, ok, err := e.entryPath(name)
+	if !ok {
+		return err
+	}
+	if err := prepare(full); err != nil {
+		return err
+	}
+	// Only the permission bits are kept, without setuid, setgid nor sticky bits.
+	file, err := os.OpenFile(full, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()|0o600)
+	if err != nil {
+		return fmt.Errorf("%w: %w", vhttp.ErrFileSystem, err)
+	}
+	if _, err := io.Copy(file, content); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("extracting '%s': %w", name, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("%w: %w", vhttp.ErrFileSystem, err)
+	}
+	e.extracted = append(e.extracted, full)
+	return nil
+}
+
+func (e *extractor) symlinkEntry(name string, target string) error {
+	relative, full, ok, err := e.entryPath(name)
+	if !ok {
+		return err
+	}
+	if err := e.checkLinkTarget(relative, target); err != nil {
+		return fmt.Errorf("%w: link '%s' points to '%s': %w", vhttp.ErrUnsafeArchive, name, target, err)
+	}
+	if err := prepare(full); err != nil {
+		return err
+	}
+	_ = os.Remove(full)
+	if err := os.Symlink(target, full); err != nil {
+		return fmt.Errorf("%w: %w", vhttp.ErrFileSystem, err)
+	}
+	e.extracted = append(e.extracted, full)
+	return nil
+}
+
+// checkLinkTarget This method returns an error if the given target of the symbolic link with the given relative path
+// is absolute, escapes the directory, or goes back with '..' after a component, as the component may be a link extracted
+// before or after this one, so the links can't be combined to escape the directory whatever their order is.
+func (e *extractor) checkLinkTarget(relative string, target string) error {
+	slashTarget := filepath.ToSlash(target)
+	if path.IsAbs(slashTarget) || filepath.IsAbs(target) || filepath.VolumeName(target) != "" {
+		return errors.New("absolute target")
+	}
+	depth := 0
+	if directory := path.Dir(relative); directory != "." {
+		depth = len(strings.Split(directory, "/"))
+	}
+	descended := false
+	for _, component := range strings.Split(slashTarget, "/") {
+		switch component {
+		case "", ".":
+		case "..":
+			if descended {
+				return errors.New("'..' after a component")
+			}
+			if depth == 0 {
+				return errors.New("outside of the directory")
+			}
+			depth--
+		default:
+			descended = true
+		}
+	}
+	return nil
+}
+
+func (e *extractor) hardLinkEntry(name string, target string) error {
+	_, full, ok, err := e.entryPath(name)
+	if !ok {
+		return err
+	}
+	components := strings.Split(strings.Trim(filepath.ToSlash(target), "/"), "/")
+	if len(components) <= e.options.StripComponents {
+		return fmt.Errorf("%w: link '%s' points to '%s' outside of the directory", vhttp.ErrUnsafeArchive, name, target)
+	}
+	relativeTarget := path.Join(components[e.options.StripComponents:]...)
+	if path.IsAbs(filepath.ToSlash(target)) || !filepath.IsLocal(filepath.FromSlash(relativeTarget)) {
+		return fmt.Errorf("%w: link '%s' points to '%s' outside of the directory", vhttp.ErrUnsafeArchive, name, target)
+	}
+	if err := e.checkParents(relativeTarget); err != nil {
+		return err
+	}
+	fullTarget := filepath.Join(e.directory, filepath.FromSlash(relativeTarget))
+	if stat, err := os.Lstat(fullTarget); err != nil || !stat.Mode().IsRegular() {
+		return fmt.Errorf("%w: link '%s' points to '%s', which is not an extracted file", vhttp.ErrUnsafeArchive, name, target)
+	}
+	if err := prepare(full); err != nil {
+		return err
+	}
+	_ = os.Remove(full)
+	if err := os.Link(fullTarget, full); err != nil {
+		return fmt.Errorf("%w: %w", vhttp.ErrFileSystem, err)
+	}
+	e.extracted = append(e.extracted, full)
+	return nil
+}
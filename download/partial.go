@@ -0,0 +1,138 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package download
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PartSuffix The suffix added to the downloads' file-names while they're in progress, the file is renamed to its final
+// name once it is complete.
+const PartSuffix = ".part"
+
+// partInfoSuffix The suffix of the file that stores the partInfo of an in progress download.
+const partInfoSuffix = ".part.json"
+
+// partInfo This struct stores the information used to validate that an in progress download can be resumed, as the
+// remote content must not change between the download's requests.
+type partInfo struct {
+	Url          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Size         int64  `json:"size"` // The complete content's size, or -1 if it's unknown.
+}
+
+// partInfoFrom This function creates a new partInfo for the given url using the validators provided by the response's
+// headers, and the given complete content's size.
+func partInfoFrom(url string, header http.Header, size int64) *partInfo {
+	return &partInfo{Url: url, ETag: header.Get("ETag"), LastModified: header.Get("Last-Modified"), Size: size}
+}
+
+// loadPartInfo This function loads the partInfo stored for the given path's download, or returns nil if it doesn't exist
+// or can't be read.
+func loadPartInfo(path string) *partInfo {
+	content, err := os.ReadFile(path + partInfoSuffix)
+	if err != nil {
+		return nil
+	}
+	info := &partInfo{}
+	if err := json.Unmarshal(content, info); err != nil {
+		return nil
+	}
+	return info
+}
+
+// save This method stores this partInfo for the given path's download.
+func (p *partInfo) save(path string) error {
+	content, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path+partInfoSuffix, content, 0o644)
+}
+
+// validator This method returns the value used for the If-Range header, so the server provides the complete content
+// instead of the requested range if it changed. Weak ETags can't be used for ranges, so the Last-Modified date is used
+// instead.
+func (p *partInfo) validator() string {
+	if p.ETag != "" && !strings.HasPrefix(p.ETag, "W/") {
+		return p.ETag
+	}
+	return p.LastModified
+}
+
+// resumable This method returns whether the download described by this partInfo can be resumed later.
+func (p *partInfo) resumable() bool {
+	return p.validator() != ""
+}
+
+// resumeOffset This function returns the amount of bytes already downloaded into the given path's part-file for the given
+// url, and its partInfo, or zero and nil if the download can't be resumed.
+func resumeOffset(path string, url string) (int64, *partInfo) {
+	info := loadPartInfo(path)
+	if info == nil || info.Url != url || !info.resumable() {
+		return 0, nil
+	}
+	stat, err := os.Stat(path + PartSuffix)
+	if err != nil || stat.Size() == 0 || (info.Size >= 0 && stat.Size() > info.Size) {
+		return 0, nil
+	}
+	return stat.Size(), info
+}
+
+// removePart This function removes the given path's part-file and its partInfo.
+func removePart(path string) {
+	_ = os.Remove(path + PartSuffix)
+	_ = os.Remove(path + partInfoSuffix)
+}
+
+// contentRange This function parses the given Content-Range header's value, returning the range's first byte and the
+// complete content's size, which is -1 if it's unknown.
+func contentRange(value string) (int64, int64, error) {
+	rangeValue, found := strings.CutPrefix(value, "bytes ")
+	if !found {
+		return 0, 0, fmt.Errorf("unsupported content-range '%s'", value)
+	}
+	positions, total, found := strings.Cut(rangeValue, "/")
+	first, _, _ := strings.Cut(positions, "-")
+	if !found {
+		return 0, 0, fmt.Errorf("malformed content-range '%s'", value)
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, errors.Join(fmt.Errorf("malformed content-range '%s'", value), err)
+	}
+	if total == "*" {
+		return start, -1, nil
+	}
+	size, err := strconv.ParseInt(total, 10, 64)
+	if err != nil {
+		return 0, 0, errors.Join(fmt.Errorf("malformed content-range '%s'", value), err)
+	}
+	return start, size, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package download
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+	vhttp "viewer/main/http"
+)
+
+// progressTracker This struct counts the bytes written by a download, and notifies its vhttp.Progress to the observer at
+// most once per vhttp.ProgressInterval. A nil progressTracker is valid and doesn't track anything.
+type progressTracker struct {
+	observer vhttp.ProgressObserver
+	name     string
+	total    int64
+	offset   int64
+	start    time.Time
+	done     atomic.Int64
+	mutex    sync.Mutex
+	notified time.Time
+	finished bool
+}
+
+// newProgressTracker This function creates a new progressTracker for the given file-name's download, whose content has the
+// given size (-1 if it's unknown), and is resumed from the given offset. It returns nil if the given observer is nil.
+func newProgressTracker(observer vhttp.ProgressObserver, name string, offset int64, total int64) *progressTracker {
+	if observer == nil {
+		return nil
+	}
+	tracker := &progressTracker{observer: observer, name: name, total: total, offset: offset, start: time.Now()}
+	tracker.done.Store(offset)
+	tracker.notify(false)
+	return tracker
+}
+
+// reader This method returns a reader that tracks the bytes read from the given reader.
+func (t *progressTracker) reader(reader io.Reader) io.Reader {
+	if t == nil {
+		return reader
+	}
+	return &progressReader{reader: reader, tracker: t}
+}
+
+// finish This method notifies the download's last vhttp.Progress, marked as finished.
+func (t *progressTracker) finish() {
+	if t == nil {
+		return
+	}
+	t.notify(true)
+}
+
+// add This method counts the given amount of bytes, and notifies the vhttp.Progress if enough time has passed since the last
+// notification.
+func (t *progressTracker) add(amount int64) {
+	t.done.Add(amount)
+	t.mutex.Lock()
+	due := time.Since(t.notified) >= vhttp.ProgressInterval
+	t.mutex.Unlock()
+	if due {
+		t.notify(false)
+	}
+}
+
+func (t *progressTracker) notify(finished bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.finished {
+		return
+	}
+	t.finished = finished
+	t.notified = time.Now()
+	t.observer.Progress(vhttp.Progress{
+		Name:     t.name,
+		Done:     t.done.Load(),
+		Total:    t.total,
+		Offset:   t.offset,
+		Elapsed:  time.Since(t.start),
+		Finished: finished,
+	})
+}
+
+// progressReader This struct wraps a reader to count the bytes read with a progressTracker.
+type progressReader struct {
+	reader  io.Reader
+	tracker *progressTracker
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.tracker.add(int64(n))
+	}
+	return n, err
+}
@@ -0,0 +1,175 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package download
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"viewer/main/async"
+	vhttp "viewer/main/http"
+	"viewer/main/utils"
+)
+
+// MinSegmentSize The minimum size of each byte range of a segmented download, smaller contents use fewer segments.
+const MinSegmentSize = int64(1 << 20)
+
+// fromSegments This function downloads the content from the given url into the given path splitting it into the given
+// amount of byte ranges, which are requested concurrently and written into the part-file at their positions, and verified
+// with the given checksumVerifier and the options' validator once complete. The returned boolean is false when the
+// content can't be segmented, in which case nothing is written and a single connection download must be used instead.
+func fromSegments(ctx context.Context, path string, url string, segments int, verifier *checksumVerifier, options ...vhttp.RequestOption) (Result, bool, error) {
+	size, info, contentType, err := probeRanges(ctx, url, options...)
+	if err != nil {
+		return Result{}, true, err
+	}
+	segments = int(min(int64(segments), size/MinSegmentSize))
+	if info == nil || segments < 2 {
+		return Result{}, false, nil
+	}
+	result := Result{HttpStatus: http.StatusPartialContent, ContentType: contentType}
+	removePart(path)
+	tracker := newProgressTracker(vhttp.DefaultConfig.Options(options...).Progress, filepath.Base(path), 0, size)
+	defer tracker.finish()
+	// The segments share the download's bandwidth limit.
+	throttle := newThrottle(vhttp.DefaultConfig.Options(options...).BandwidthLimit)
+	file, err := os.OpenFile(path+PartSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return result, true, fmt.Errorf("%w: %w", vhttp.ErrFileSystem, err)
+	}
+	completed := false
+	// [os.File] object closing, the part-file is removed if the download wasn't completed, as segments can't be resumed.
+	defer func(File *os.File) {
+		_ = File.Close()
+		if !completed {
+			removePart(path)
+		}
+	}(file)
+	if err := file.Truncate(size); err != nil {
+		return result, true, fmt.Errorf("%w: %w", vhttp.ErrFileSystem, err)
+	}
+	// Stop the remaining segments as soon as one of them fails.
+	segmentsCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	segmentSize := size / int64(segments)
+	fns := make([]func(context.Context) (int64, error), segments)
+	for index := range fns {
+		start := int64(index) * segmentSize
+		end := start + segmentSize - 1
+		if index == segments-1 {
+			end = size - 1
+		}
+		fns[index] = func(ctx context.Context) (int64, error) {
+			read, err := downloadSegment(ctx, file, tracker, throttle, url, start, end, info, options...)
+			if err != nil {
+				cancel(err)
+			}
+			return read, err
+		}
+	}
+	// Execute waits for every segment to return, even after a failure, so none of them is still writing into the part-file
+	// when it's closed and removed.
+	if _, err := async.Execute(segmentsCtx, segments, fns...); err != nil {
+		if ctx.Err() != nil {
+			return result, true, ctx.Err()
+		}
+		return result, true, context.Cause(segmentsCtx)
+	}
+	if err := file.Close(); err != nil {
+		return result, true, fmt.Errorf("%w: %w", vhttp.ErrFileSystem, err)
+	}
+	// The segments are written out of order, so the content is hashed once it's complete.
+	if err := verifier.start(path+PartSuffix, size); err != nil {
+		return result, true, err
+	}
+	if err := verifier.verify(); err != nil {
+		return result, true, err
+	}
+	if err := validate(path+PartSuffix, options...); err != nil {
+		return result, true, err
+	}
+	finalPath, err := CommitPart(path, vhttp.DefaultConfig.Options(options...).ConflictPolicy)
+	if err != nil {
+		return result, true, err
+	}
+	completed = true
+	result.Path, result.Bytes, result.Sha256 = finalPath, size, verifier.sum()
+	return result, true, nil
+}
+
+// probeRanges This function requests the given url's first byte to check whether the server supports ranges, returning the
+// content's complete size, its partInfo, which is nil if the content can't be segmented, and its content-type.
+func probeRanges(ctx context.Context, url string, options ...vhttp.RequestOption) (int64, *partInfo, string, error) {
+	resp, err := utils.OriginalResponseContext(ctx, url, slices.Concat(options, []vhttp.RequestOption{
+		vhttp.WithHeader("Range", "bytes=0-0"),
+	})...)
+	if err != nil {
+		return 0, nil, "", err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, nil, "", nil
+	}
+	start, size, err := contentRange(resp.Header.Get("Content-Range"))
+	info := partInfoFrom(url, resp.Header, size)
+	if err != nil || start != 0 || size <= 0 || !info.resumable() {
+		return 0, nil, "", nil
+	}
+	return size, info, resp.Header.Get("Content-Type"), nil
+}
+
+// downloadSegment This function requests the given url's byte range and writes it into the given file at the same
+// position, tracking the written bytes with the given progressTracker and limiting them with the given throttle. The range
+// is validated by the given partInfo, so the content can't change between the segments' requests.
+func downloadSegment(ctx context.Context, file *os.File, tracker *progressTracker, throttle *throttle, url string, start int64, end int64, info *partInfo, options ...vhttp.RequestOption) (int64, error) {
+	resp, err := utils.OriginalResponseContext(ctx, url, slices.Concat(options, []vhttp.RequestOption{
+		vhttp.WithHeader("Range", fmt.Sprintf("bytes=%d-%d", start, end)),
+		vhttp.WithHeader("If-Range", info.validator()),
+	})...)
+	if err != nil {
+		return 0, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, fmt.Errorf("%w: '%s' changed during the segmented download", vhttp.ErrNetwork, url)
+	}
+	if first, _, err := contentRange(resp.Header.Get("Content-Range")); err != nil || first != start {
+		return 0, fmt.Errorf("%w: '%s' provided an unexpected range '%s'", vhttp.ErrNetwork, url, resp.Header.Get("Content-Range"))
+	}
+	read, err := io.Copy(io.NewOffsetWriter(file, start), io.LimitReader(tracker.reader(throttle.reader(ctx, resp.Body)), end-start+1))
+	if err != nil {
+		if ctx.Err() != nil {
+			return read, ctx.Err()
+		}
+		return read, fmt.Errorf("%w: copying '%s' range %d-%d: %w", vhttp.ErrNetwork, url, start, end, err)
+	}
+	if read != end-start+1 {
+		return read, fmt.Errorf("%w: '%s' provided %d of %d bytes for range %d-%d", vhttp.ErrNetwork, url, read, end-start+1, start, end)
+	}
+	return read, nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package download
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+	vhttp "viewer/main/http"
+)
+
+// maxThrottledRead The maximum amount of bytes read at once by a throttled download, so the waits are short and the
+// bandwidth is shared evenly between the concurrent downloads.
+const maxThrottledRead = 32 << 10
+
+var (
+	sharedLimiterMutex sync.Mutex
+	sharedLimiter      *bandwidthLimiter // The limiter shared by all the downloads, for the vhttp.Config's BandwidthLimit.
+)
+
+// bandwidthLimiter This struct is a token bucket that limits the amount of bytes read per second by one or more readers,
+// the readers reserve the bytes they read, waiting until the bucket refills enough to cover them.
+type bandwidthLimiter struct {
+	rate     int64
+	capacity int64
+	mutex    sync.Mutex
+	tokens   float64
+	last     time.Time
+}
+
+// newBandwidthLimiter This function creates a new bandwidthLimiter for the given bytes per second, which must be positive.
+func newBandwidthLimiter(rate int64) *bandwidthLimiter {
+	capacity := min(max(rate/4, 1), maxThrottledRead)
+	return &bandwidthLimiter{rate: rate, capacity: capacity, tokens: float64(capacity), last: time.Now()}
+}
+
+// wait This method reserves the given amount of bytes, and waits until the bucket covers them, returning the context's error
+// if the given context is done before.
+func (l *bandwidthLimiter) wait(ctx context.Context, amount int) error {
+	l.mutex.Lock()
+	now := time.Now()
+	l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*float64(l.rate), float64(l.capacity))
+	l.last = now
+	// The tokens may be negative, so the next readers wait for the bytes reserved before them.
+	l.tokens -= float64(amount)
+	delay := time.Duration(-l.tokens / float64(l.rate) * float64(time.Second))
+	l.mutex.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// throttle This struct holds the bandwidthLimiter list applied to a download, including the one shared by all the
+// downloads. A nil throttle is valid and doesn't limit anything.
+type throttle struct {
+	limiters []*bandwidthLimiter
+}
+
+// newThrottle This function creates a new throttle for a download limited to the given bytes per second, and to the
+// vhttp.DefaultConfig's BandwidthLimit together with the other downloads. It returns nil if there aren't limits.
+func newThrottle(limit int64) *throttle {
+	limiters := make([]*bandwidthLimiter, 0, 2)
+	if limit > 0 {
+		limiters = append(limiters, newBandwidthLimiter(limit))
+	}
+	if shared := sharedBandwidthLimiter(vhttp.DefaultConfig.BandwidthLimit); shared != nil {
+		limiters = append(limiters, shared)
+	}
+	if len(limiters) == 0 {
+		return nil
+	}
+	return &throttle{limiters: limiters}
+}
+
+// sharedBandwidthLimiter This function returns the bandwidthLimiter shared by all the downloads for the given bytes per
+// second, which is replaced when the limit changes, or nil if the limit is not positive.
+func sharedBandwidthLimiter(limit int64) *bandwidthLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	sharedLimiterMutex.Lock()
+	defer sharedLimiterMutex.Unlock()
+	if sharedLimiter == nil || sharedLimiter.rate != limit {
+		sharedLimiter = newBandwidthLimiter(limit)
+	}
+	return sharedLimiter
+}
+
+// reader This method returns a reader that limits the bandwidth used to read from the given reader, the waits are aborted
+// when the given context is done.
+func (t *throttle) reader(ctx context.Context, reader io.Reader) io.Reader {
+	if t == nil {
+		return reader
+	}
+	capacity := int64(maxThrottledRead)
+	for _, limiter := range t.limiters {
+		capacity = min(capacity, limiter.capacity)
+	}
+	return &throttledReader{ctx: ctx, reader: reader, throttle: t, chunk: int(capacity)}
+}
+
+// throttledReader This struct wraps a reader to wait for the throttle's limiters after each read.
+type throttledReader struct {
+	ctx      context.Context
+	reader   io.Reader
+	throttle *throttle
+	chunk    int
+}
+
+func (r *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > r.chunk {
+		p = p[:r.chunk]
+	}
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		for _, limiter := range r.throttle.limiters {
+			if err := limiter.wait(r.ctx, n); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package download
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+	vhttp "viewer/main/http"
+	"viewer/main/utils"
+)
+
+// ToWriter This function downloads the content from the given url into the given writer, such as the standard output of
+// a pipeline, and returns the download's Result and error as From does. The given name identifies
+// the content in the progress and the errors. As the content is written while it's downloaded, it can't be resumed nor
+// segmented, the conflict policy is not used, and a vhttp.ChecksumError is returned after the content was written when
+// it doesn't match the checksum specified with the vhttp.WithChecksum option, so the writer's consumer must discard it.
+// The bandwidth limits and the progress observer are used as From does.
+func ToWriter(writer io.Writer, name string, url string, options ...vhttp.RequestOption) (Result, error) {
+	return ToWriterContext(context.Background(), writer, name, url, options...)
+}
+
+// ToWriterContext This function realizes the same execution that ToWriter, the download is aborted when the given context
+// is done.
+func ToWriterContext(ctx context.Context, writer io.Writer, name string, url string, options ...vhttp.RequestOption) (Result, error) {
+	start := time.Now()
+	result, err := toWriter(ctx, writer, name, url, options...)
+	return result.finish(start, err)
+}
+
+// toWriter This function downloads the content from the given url into the given writer as described by ToWriter.
+func toWriter(ctx context.Context, writer io.Writer, name string, url string, options ...vhttp.RequestOption) (Result, error) {
+	if !validGithubUrl(url) {
+		return Result{Status: InvalidAssetUrlStatus}, fmt.Errorf("%w: '%s' doesn't belong to '%s'", vhttp.ErrInvalidAssetUrl, url, vhttp.DefaultConfig.BaseUrl())
+	}
+	requestOptions := vhttp.DefaultConfig.Options(options...)
+	verifier, err := newChecksumVerifier(name, requestOptions.Checksum)
+	if err != nil {
+		return Result{}, err
+	}
+	resp, err := utils.OriginalResponseContext(ctx, url, options...)
+	if err != nil {
+		return Result{}, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	result := Result{HttpStatus: resp.StatusCode, ContentType: resp.Header.Get("Content-Type")}
+	tracker := newProgressTracker(requestOptions.Progress, name, 0, resp.ContentLength)
+	defer tracker.finish()
+	throttle := newThrottle(requestOptions.BandwidthLimit)
+	destination := &recordingWriter{writer: writer}
+	read, err := io.Copy(verifier.writer(destination), tracker.reader(throttle.reader(ctx, resp.Body)))
+	result.Bytes = read
+	if err != nil {
+		if destination.err != nil {
+			return result, fmt.Errorf("%w: writing '%s': %w", vhttp.ErrFileSystem, name, destination.err)
+		}
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		return result, fmt.Errorf("%w: copying '%s': %w", vhttp.ErrNetwork, url, err)
+	}
+	if resp.ContentLength >= 0 && read != resp.ContentLength {
+		return result, fmt.Errorf("%w: '%s' provided %d of %d bytes", vhttp.ErrNetwork, url, read, resp.ContentLength)
+	}
+	if err := verifier.verify(); err != nil {
+		return result, err
+	}
+	result.Sha256 = verifier.sum()
+	return result, nil
+}
+
+// recordingWriter This struct wraps a writer to keep its last error, so the writer's failures can be distinguished from
+// the reader's ones.
+type recordingWriter struct {
+	writer io.Writer
+	err    error
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	if err != nil {
+		w.err = err
+	}
+	return n, err
+}
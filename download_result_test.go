@@ -0,0 +1,89 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+	"viewer/main/download"
+	"viewer/main/http"
+	"viewer/main/repository"
+)
+
+func TestDownloadResult(t *testing.T) {
+	content := bytes.Repeat([]byte("result-content"), 300_000)
+	digest := sha256.Sum256(content)
+	checksum := hex.EncodeToString(digest[:])
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.URL.Path == "/missing.bin" {
+			nethttp.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/x-test")
+		nethttp.ServeContent(w, r, "asset.bin", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+	directory := t.TempDir()
+
+	result, err := download.From(directory, "asset.bin", server.URL+"/asset.bin")
+	if err != nil || !result.Downloaded() || result.Err != nil || result.Path != filepath.Join(directory, "asset.bin") ||
+		result.Bytes != int64(len(content)) || result.Sha256 != checksum || result.HttpStatus != nethttp.StatusOK ||
+		result.ContentType != "application/x-test" || result.Resumed || result.Duration <= 0 {
+		t.Errorf("Unexpected download result: %+v (%v)", result, err)
+	}
+
+	result, err = download.From(directory, "segmented.bin", server.URL+"/asset.bin", http.WithSegments(2))
+	if err != nil || result.Sha256 != checksum || result.HttpStatus != nethttp.StatusPartialContent || result.ContentType != "application/x-test" {
+		t.Errorf("Unexpected segmented download result: %+v (%v)", result, err)
+	}
+
+	result, err = download.From(directory, "asset.bin", server.URL+"/asset.bin", http.WithConflictPolicy(http.ConflictSkipIdentical))
+	if err != nil || !result.Skipped() || result.Status.String() != "skipped" || result.Sha256 != checksum || result.HttpStatus != 0 {
+		t.Errorf("Unexpected skipped download result: %+v (%v)", result, err)
+	}
+
+	result, err = download.From(directory, "missing.bin", server.URL+"/missing.bin")
+	if !errors.Is(err, http.ErrNotFound) || !result.Error() || result.Err != err || result.HttpStatus != nethttp.StatusNotFound || result.Sha256 != "" {
+		t.Errorf("Unexpected failed download result: %+v (%v)", result, err)
+	}
+	if result, err = download.From(directory, "asset.bin", "https://example.com/asset.bin"); !result.InvalidUrl() || err == nil {
+		t.Errorf("Unexpected invalid url download result: %+v (%v)", result, err)
+	}
+
+	model := &repository.GithubReleaseModel{Assets: []repository.Asset{{Name: "release.bin", Url: server.URL + "/asset.bin"}}}
+	result, err = model.Download(directory, 0)
+	if err != nil || !result.Downloaded() || result.Sha256 != checksum || result.Path != filepath.Join(directory, "release.bin") {
+		t.Errorf("Unexpected release's download result: %+v (%v)", result, err)
+	}
+	if result, err = model.Download(directory, 5); !errors.Is(err, http.ErrInvalidAssetIndex) || !result.Error() {
+		t.Errorf("Unexpected invalid index download result: %+v (%v)", result, err)
+	}
+}
@@ -24,22 +24,21 @@ package main
 import (
 	"testing"
 	"time"
-	"viewer/main/download"
 	"viewer/main/http"
 	"viewer/main/repository"
 )
 
 func TestAssetDownload(t *testing.T) {
 	releaseRequest := repository.NewReleaseRequest(ForRelease("aivruu", "repo-viewer", "v3.4.7"))
-	release := http.Request(releaseRequest, 5*time.Second)
-	if release == nil {
-		t.Error("Failed to request the release for this repository.")
+	release, err := http.Request(releaseRequest, 5*time.Second)
+	if err != nil {
+		t.Error("Failed to request the release for this repository.", err)
 		return
 	}
-	read := release.Download("", 0) // Download the first asset at this directory.
-	if read == download.InvalidAssetDefaultSize || read == download.UnknownAssetDefaultSize {
-		t.Error("Failed to download the asset.")
+	result, err := release.Download("", 0) // Download the first asset at this directory.
+	if err != nil || result.Unknown() {
+		t.Error("Failed to download the asset.", err)
 		return
 	}
-	t.Logf("Asset downloaded: %d bytes read", read)
+	t.Logf("Asset downloaded: %d bytes read", result.Bytes)
 }
@@ -0,0 +1,78 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"viewer/main/download"
+	"viewer/main/http"
+	"viewer/main/repository"
+)
+
+func TestTypedErrors(t *testing.T) {
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		switch r.URL.Path {
+		case "/repos/aivruu/broken-viewer":
+			_, _ = w.Write([]byte(`{"name": 1}`))
+		case "/repos/aivruu/private-viewer":
+			w.WriteHeader(nethttp.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"Bad credentials"}`))
+		default:
+			w.WriteHeader(nethttp.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+
+	_, err := http.Request(repository.NewRepositoryRequest(ForRepository("aivruu", "missing-viewer")), 5*time.Second)
+	var statusErr *http.StatusError
+	if !errors.Is(err, http.ErrNotFound) || !errors.As(err, &statusErr) || statusErr.Message != "Not Found" {
+		t.Errorf("Expected a not-found error, got '%v'.", err)
+	}
+	if _, err = http.Request(repository.NewRepositoryRequest(ForRepository("aivruu", "private-viewer")), 5*time.Second); !errors.Is(err, http.ErrUnauthorized) {
+		t.Errorf("Expected an unauthorized error, got '%v'.", err)
+	}
+	if _, err = http.Request(repository.NewRepositoryRequest(ForRepository("aivruu", "broken-viewer")), 5*time.Second); !errors.Is(err, http.ErrDecode) {
+		t.Errorf("Expected a decoding error, got '%v'.", err)
+	}
+	if _, err = download.From(t.TempDir(), "asset.bin", "https://example.com/asset.bin"); !errors.Is(err, http.ErrInvalidAssetUrl) {
+		t.Errorf("Expected an invalid asset url error, got '%v'.", err)
+	}
+	directory := t.TempDir()
+	if _, err = download.From(directory, "asset.bin", server.URL+"/missing.bin"); !errors.Is(err, http.ErrNotFound) {
+		t.Errorf("Expected a not-found error for the download, got '%v'.", err)
+	}
+	if _, err := os.Stat(filepath.Join(directory, "asset.bin")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected no file for the failed download, got '%v'.", err)
+	}
+	release := repository.GithubReleaseModel{}
+	if _, err = release.Download(directory, 0); !errors.Is(err, http.ErrInvalidAssetIndex) {
+		t.Errorf("Expected an invalid asset index error, got '%v'.", err)
+	}
+}
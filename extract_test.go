@@ -0,0 +1,142 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"viewer/main/download"
+	"viewer/main/http"
+)
+
+// archiveEntry This struct describes an entry of the archives created by the tests, a link if the target is not empty.
+type archiveEntry struct {
+	name    string
+	content string
+	target  string
+}
+
+func writeTarGz(t *testing.T, path string, entries []archiveEntry) {
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gzipWriter := gzip.NewWriter(file)
+	defer gzipWriter.Close()
+	tarWriter := tar.NewWriter(gzipWriter)
+	defer tarWriter.Close()
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: 0o644, Size: int64(len(entry.content)), Typeflag: tar.TypeReg}
+		if entry.target != "" {
+			header = &tar.Header{Name: entry.name, Linkname: entry.target, Typeflag: tar.TypeSymlink}
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		_, _ = tarWriter.Write([]byte(entry.content))
+	}
+}
+
+func writeZip(t *testing.T, path string, entries []archiveEntry) {
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	zipWriter := zip.NewWriter(file)
+	defer zipWriter.Close()
+	for _, entry := range entries {
+		writer, err := zipWriter.Create(entry.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = writer.Write([]byte(entry.content))
+	}
+}
+
+func TestArchiveExtraction(t *testing.T) {
+	directory := t.TempDir()
+	archive := filepath.Join(directory, "app.tar.gz")
+	writeTarGz(t, archive, []archiveEntry{
+		{name: "app-1.0/bin/app", content: "binary"},
+		{name: "app-1.0/bin/app-link", target: "app"},
+		{name: "app-1.0/bin/app-current", target: "app-link"},
+		{name: "app-1.0/LICENSE", content: "MIT"},
+		{name: "app-1.0/docs/README", content: "readme"},
+	})
+	target := filepath.Join(directory, "out")
+	files, err := download.Extract(archive, target, download.ExtractOptions{StripComponents: 1, Include: []string{"bin", "LICENSE"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{filepath.Join(target, "bin", "app"), filepath.Join(target, "bin", "app-link"), filepath.Join(target, "bin", "app-current"), filepath.Join(target, "LICENSE")}
+	if !slices.Equal(files, expected) {
+		t.Errorf("Unexpected extracted files: %v", files)
+	}
+	if content, err := os.ReadFile(filepath.Join(target, "bin", "app-current")); err != nil || string(content) != "binary" {
+		t.Errorf("Unexpected link's content '%s' (%v)", content, err)
+	}
+
+	zipArchive := filepath.Join(directory, "app.zip")
+	writeZip(t, zipArchive, []archiveEntry{{name: "app/tool", content: "tool"}})
+	if files, err := download.Extract(zipArchive, target, download.ExtractOptions{}); err != nil || len(files) != 1 {
+		t.Errorf("Unexpected zip extraction: %v (%v)", files, err)
+	}
+	if _, err := download.Extract(filepath.Join(directory, "app.exe"), target, download.ExtractOptions{}); !errors.Is(err, http.ErrUnsupportedArchive) {
+		t.Errorf("Expected an unsupported archive error, got '%v'.", err)
+	}
+}
+
+func TestUnsafeArchiveExtraction(t *testing.T) {
+	directory := t.TempDir()
+	outside := filepath.Join(directory, "outside")
+	malicious := map[string][]archiveEntry{
+		"traversal": {{name: "../outside/evil", content: "evil"}},
+		"absolute":  {{name: "/outside/evil", content: "evil"}},
+		"link":      {{name: "evil", target: "../outside"}},
+		"through":   {{name: "dir", target: "."}, {name: "dir/evil", content: "evil"}},
+		"chained":   {{name: "sub/up", target: ".."}, {name: "sub/up2", target: "up/.."}},
+		"ordering":  {{name: "d/a", target: "b/../secret"}, {name: "d/b", target: ".."}},
+	}
+	for name, entries := range malicious {
+		archive := filepath.Join(directory, name+".tar.gz")
+		writeTarGz(t, archive, entries)
+		if _, err := download.Extract(archive, filepath.Join(directory, name), download.ExtractOptions{}); !errors.Is(err, http.ErrUnsafeArchive) {
+			t.Errorf("Expected an unsafe archive error for '%s', got '%v'.", name, err)
+		}
+	}
+	zipArchive := filepath.Join(directory, "traversal.zip")
+	writeZip(t, zipArchive, []archiveEntry{{name: "../outside/evil", content: "evil"}})
+	if _, err := download.Extract(zipArchive, filepath.Join(directory, "zip"), download.ExtractOptions{}); !errors.Is(err, http.ErrUnsafeArchive) {
+		t.Errorf("Expected an unsafe archive error for the zip, got '%v'.", err)
+	}
+	if _, err := os.Stat(outside); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected nothing to be written outside of the directory, got '%v'.", err)
+	}
+}
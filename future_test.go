@@ -0,0 +1,183 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+	"viewer/main/async"
+)
+
+func TestFutureMemoizedResult(t *testing.T) {
+	calls := 0
+	f := async.NewFuture(func() (int, error) {
+		calls++
+		return 42, nil
+	})
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if value, err := f.Get(); value != 42 || err != nil {
+				t.Errorf("Unexpected result: %d (%v)", value, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("Expected a single execution, got %d.", calls)
+	}
+
+	chained := async.Map(async.Then(f, func(value int) (int, error) {
+		return value * 2, nil
+	}), strconv.Itoa)
+	if value, err := chained.Get(); value != "84" || err != nil {
+		t.Errorf("Unexpected chained result: '%s' (%v)", value, err)
+	}
+
+	failure := errors.New("failed")
+	failed := async.Then(async.Completed(0, failure), func(int) (int, error) {
+		t.Error("The function must not be executed for a failed future.")
+		return 0, nil
+	})
+	if _, err := failed.Get(); !errors.Is(err, failure) {
+		t.Errorf("Expected the failure to be propagated, got '%v'.", err)
+	}
+}
+
+func TestFutureTimeoutAndCancellation(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	slow := async.NewFuture(func() (int, error) {
+		<-release
+		return 1, nil
+	})
+	if _, err := slow.GetWithTimeout(10 * time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a timeout, got '%v'.", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := async.NewFutureContext(ctx, func(ctx context.Context) (int, error) {
+		<-release
+		return 1, nil
+	})
+	cancel()
+	if _, err := cancelled.Get(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancellation, got '%v'.", err)
+	}
+
+	panicked := async.NewFuture(func() (int, error) {
+		panic("unexpected")
+	})
+	if _, err := panicked.Get(); err == nil {
+		t.Error("Expected the panic to be reported as an error.")
+	}
+}
+
+func TestFutureCombinators(t *testing.T) {
+	failure := errors.New("failed")
+	values, err := async.All(async.Completed(1, nil), async.Completed(0, failure), async.Completed(3, nil)).Get()
+	if !errors.Is(err, failure) || len(values) != 3 || values[0] != 1 || values[2] != 3 {
+		t.Errorf("Unexpected result: %v (%v)", values, err)
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+	blocked := async.NewFuture(func() (int, error) {
+		<-release
+		return 1, nil
+	})
+	if value, err := async.Any(async.Completed(0, failure), blocked, async.Completed(2, nil)).Get(); value != 2 || err != nil {
+		t.Errorf("Expected the first success, got %d (%v)", value, err)
+	}
+	if _, err := async.Any(async.Completed(0, failure), async.Completed(0, failure)).Get(); !errors.Is(err, failure) {
+		t.Errorf("Expected the joined failures, got '%v'.", err)
+	}
+	if _, err := async.Any[int]().Get(); !errors.Is(err, async.ErrNoFutures) {
+		t.Errorf("Expected no futures error, got '%v'.", err)
+	}
+}
+
+func TestPoolLimit(t *testing.T) {
+	var mutex sync.Mutex
+	running, peak := 0, 0
+	fns := make([]func(context.Context) (int, error), 10)
+	for index := range fns {
+		fns[index] = func(context.Context) (int, error) {
+			mutex.Lock()
+			running++
+			peak = max(peak, running)
+			mutex.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+			return index, nil
+		}
+	}
+	values, err := async.Execute(context.Background(), 3, fns...)
+	if err != nil || len(values) != 10 || values[9] != 9 {
+		t.Fatalf("Unexpected result: %v (%v)", values, err)
+	}
+	if peak > 3 {
+		t.Errorf("Expected at most 3 concurrent functions, got %d.", peak)
+	}
+}
+
+func TestExecuteWaitsForCancelledFunctions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var mutex sync.Mutex
+	started, returned := 0, 0
+	fns := make([]func(context.Context) (int, error), 4)
+	for index := range fns {
+		fns[index] = func(ctx context.Context) (int, error) {
+			mutex.Lock()
+			started++
+			// Cancel once the pool is full, so the remaining functions are never started.
+			if started == 2 {
+				cancel()
+			}
+			mutex.Unlock()
+			<-ctx.Done()
+			// Keep working for a while after the cancellation, as a download closing its file would.
+			time.Sleep(20 * time.Millisecond)
+			mutex.Lock()
+			returned++
+			mutex.Unlock()
+			return index, nil
+		}
+	}
+	if _, err := async.Execute(ctx, 2, fns...); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the execution to be cancelled, got '%v'.", err)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if returned != started {
+		t.Errorf("Expected the %d started functions to return before Execute, got %d.", started, returned)
+	}
+}
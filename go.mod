@@ -1,3 +1,7 @@
 module viewer/main
 
 go 1.23.2
+
+require golang.org/x/crypto v0.36.0
+
+require golang.org/x/sys v0.31.0 // indirect
@@ -0,0 +1,57 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// bandwidthUnits The multipliers of the bandwidth's suffixes accepted by ParseBandwidth, which use binary units as curl.
+var bandwidthUnits = map[string]float64{"": 1, "K": 1 << 10, "M": 1 << 20, "G": 1 << 30}
+
+// ParseBandwidth This function parses a bandwidth in bytes per second, written as a number optionally followed by the K, M
+// or G binary units, such as "500K" or "1.5M", and returns it. Zero disables the limit.
+func ParseBandwidth(text string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(text))
+	value = strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(value, "/S"), "B"), "I")
+	unit := ""
+	if value != "" && strings.ContainsAny(value[len(value)-1:], "KMG") {
+		unit = value[len(value)-1:]
+		value = value[:len(value)-1]
+	}
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount < 0 {
+		return 0, fmt.Errorf("invalid bandwidth '%s': expected an amount like '500K' or '5M'", text)
+	}
+	return int64(amount * bandwidthUnits[unit]), nil
+}
+
+// WithBandwidthLimit This function returns a RequestOption that limits the download's bandwidth to the given bytes per
+// second, it is applied together with the Config's BandwidthLimit shared by all the downloads. Not positive values disable
+// the download's own limit.
+func WithBandwidthLimit(bytesPerSecond int64) RequestOption {
+	return func(options *RequestOptions) {
+		options.BandwidthLimit = bytesPerSecond
+	}
+}
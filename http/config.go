@@ -0,0 +1,218 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+	"viewer/main/cache"
+)
+
+const (
+	DefaultBaseUrl    = "https://api.github.com" // The public GitHub API's base URL.
+	BaseUrlEnv        = "GVW_API_URL"            // Environment variable used to override the API's base URL.
+	ActionsBaseUrlEnv = "GITHUB_API_URL"         // Environment variable set by GitHub Actions runners, also on Enterprise Server.
+	LatestReleaseTag  = "latest"                 // Special tag used to request the repository's latest release.
+	TokenEnv          = "GH_TOKEN"               // Environment variable used to provide the authentication token.
+	ActionsTokenEnv   = "GITHUB_TOKEN"           // Environment variable used as fallback for the authentication token.
+)
+
+// Config This struct holds the client-level settings shared by every request and download, such as the GitHub API's base
+// URL used to build the requests' URLs and to validate the assets' download URLs.
+type Config struct {
+	baseUrl          *url.URL
+	Token            string          // The token sent to the configured GitHub instance, requests are anonymous if it is empty.
+	RateLimitPolicy  RateLimitPolicy // The behavior used when a request exceeds the rate limit.
+	MaxRateLimitWait time.Duration   // The longest wait accepted by the RateLimitWait policy, longer waits fail instead.
+	Retry            RetryPolicy     // The policy used to repeat the requests failed due to transient errors.
+	Cache            *cache.Store    // The store used to cache and revalidate the API's responses, disabled if it is nil.
+	MaxResponseSize  int64           // The maximum size of the API's responses' bodies, not positive values disable it.
+	Segments         int             // The amount of concurrent connections used by each download, one disables segmentation.
+	Verify           bool            // Whether the releases' assets' downloads are verified against their checksums.
+	ConflictPolicy   ConflictPolicy  // The behavior used when a download's file already exists.
+
+	// BandwidthLimit The maximum bytes per second read by all the downloads together, not positive values disable it.
+	BandwidthLimit int64
+	// DownloadBandwidthLimit The maximum bytes per second read by each download, not positive values disable it.
+	DownloadBandwidthLimit int64
+
+	// TrustedKeys The public keys trusted for each repository ("owner/name", compared case-insensitively), the assets
+	// downloaded from a repository with trusted keys must be signed by one of them, otherwise they're discarded.
+	TrustedKeys map[string][]string
+}
+
+// DefaultConfig The Config used by the package-level helpers and the download package, it is initialized using the
+// environment variables and can be modified by library users before making any request.
+var DefaultConfig = NewConfigFromEnv()
+
+// NewConfig This function creates a new Config using the given base URL, returning an error if the URL is not valid.
+func NewConfig(baseUrl string) (*Config, error) {
+	config := &Config{MaxRateLimitWait: DefaultMaxRateLimitWait, Retry: DefaultRetryPolicy, MaxResponseSize: DefaultMaxResponseSize, Segments: 1}
+	if err := config.SetBaseUrl(baseUrl); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// NewConfigFromEnv This function creates a new Config using the BaseUrlEnv or ActionsBaseUrlEnv environment variables for
+// the base URL, falling back to the DefaultBaseUrl if none of them is set or valid, and the TokenEnv or ActionsTokenEnv
+// environment variables for the token.
+func NewConfigFromEnv() *Config {
+	config, _ := NewConfig(DefaultBaseUrl)
+	for _, env := range []string{BaseUrlEnv, ActionsBaseUrlEnv} {
+		if value := os.Getenv(env); value != "" {
+			if config.SetBaseUrl(value) == nil {
+				break
+			}
+		}
+	}
+	for _, env := range []string{TokenEnv, ActionsTokenEnv} {
+		if value := os.Getenv(env); value != "" {
+			config.Token = value
+			break
+		}
+	}
+	return config
+}
+
+// SetBaseUrl This method validates and sets the API's base URL, for GitHub Enterprise Server it usually looks like
+// "https://<hostname>/api/v3".
+func (c *Config) SetBaseUrl(baseUrl string) error {
+	parsed, err := url.Parse(strings.TrimRight(baseUrl, "/"))
+	if err != nil {
+		return fmt.Errorf("invalid API base URL '%s': %w", baseUrl, err)
+	}
+	if parsed.Scheme != "https" && parsed.Scheme != "http" {
+		return fmt.Errorf("invalid API base URL '%s': scheme must be http or https", baseUrl)
+	}
+	if parsed.Host == "" {
+		return errors.New("invalid API base URL '" + baseUrl + "': missing host")
+	}
+	c.baseUrl = parsed
+	return nil
+}
+
+// BaseUrl This method returns the API's base URL without trailing slash.
+func (c *Config) BaseUrl() string {
+	return c.baseUrl.String()
+}
+
+// RepositoryUrl This method returns the API's URL for the given author's repository.
+func (c *Config) RepositoryUrl(author, repository string) string {
+	return c.BaseUrl() + "/repos/" + url.PathEscape(author) + "/" + url.PathEscape(repository)
+}
+
+// ReleaseUrl This method returns the API's URL for the repository's release with the given tag, or for the latest release
+// if the tag is LatestReleaseTag.
+func (c *Config) ReleaseUrl(author, repository, tag string) string {
+	if tag == LatestReleaseTag {
+		return c.RepositoryUrl(author, repository) + "/releases/latest"
+	}
+	return c.RepositoryUrl(author, repository) + "/releases/tags/" + url.PathEscape(tag)
+}
+
+// ReleasesUrl This method returns the API's URL for the list of the repository's releases.
+func (c *Config) ReleasesUrl(author, repository string) string {
+	return c.RepositoryUrl(author, repository) + "/releases"
+}
+
+// TagsUrl This method returns the API's URL for the list of the repository's tags.
+func (c *Config) TagsUrl(author, repository string) string {
+	return c.RepositoryUrl(author, repository) + "/tags"
+}
+
+// ValidDownloadUrl This method returns whether the given URL points to the API's host or to its web host (the API's host
+// without the "api." prefix, like github.com for api.github.com), using the same scheme as the base URL.
+func (c *Config) ValidDownloadUrl(downloadUrl string) bool {
+	parsed, err := url.Parse(downloadUrl)
+	if err != nil || parsed.Scheme != c.baseUrl.Scheme {
+		return false
+	}
+	return c.TrustedHost(parsed.Host)
+}
+
+// TrustedHost This method returns whether the given host (including the port, if any) is the API's host or its web host.
+func (c *Config) TrustedHost(host string) bool {
+	apiHost := strings.ToLower(c.baseUrl.Host)
+	host = strings.ToLower(host)
+	return host == apiHost || host == strings.TrimPrefix(apiHost, "api.")
+}
+
+// Authorize This method adds the token to the given request's Authorization header, only if there is a token and the request
+// is made to the configured GitHub instance using the base URL's scheme.
+func (c *Config) Authorize(req *http.Request) {
+	if c.Token == "" || req.URL.Scheme != c.baseUrl.Scheme || !c.TrustedHost(req.URL.Host) {
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+}
+
+// RateLimitUrl This method returns the API's URL used to check the rate-limit status, requests to it don't count against
+// the rate limit.
+func (c *Config) RateLimitUrl() string {
+	return c.BaseUrl() + "/rate_limit"
+}
+
+// TrustKey This method adds the given public key to the repository's (as "owner/name") trusted keys. The repository's
+// name is stored in lower-case, as GitHub's owners and repositories' names are case-insensitive.
+func (c *Config) TrustKey(repository string, key string) {
+	if c.TrustedKeys == nil {
+		c.TrustedKeys = make(map[string][]string)
+	}
+	repository = strings.ToLower(repository)
+	c.TrustedKeys[repository] = append(c.TrustedKeys[repository], key)
+}
+
+// RepositoryKeys This method returns the public keys trusted for the given repository (as "owner/name"), comparing the
+// repositories' names case-insensitively, as they may be added to the TrustedKeys directly.
+func (c *Config) RepositoryKeys(repository string) []string {
+	keys := make([]string, 0)
+	for name, repositoryKeys := range c.TrustedKeys {
+		if strings.EqualFold(name, repository) {
+			keys = append(keys, repositoryKeys...)
+		}
+	}
+	return keys
+}
+
+// Options This method returns the RequestOptions based on this Config's settings, modified by the given RequestOption list.
+func (c *Config) Options(options ...RequestOption) RequestOptions {
+	requestOptions := RequestOptions{
+		Retry:            c.Retry,
+		RateLimitPolicy:  c.RateLimitPolicy,
+		MaxRateLimitWait: c.MaxRateLimitWait,
+		MaxResponseSize:  c.MaxResponseSize,
+		Segments:         c.Segments,
+		Verify:           c.Verify,
+		ConflictPolicy:   c.ConflictPolicy,
+		BandwidthLimit:   c.DownloadBandwidthLimit,
+	}
+	for _, option := range options {
+		option(&requestOptions)
+	}
+	return requestOptions
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package http
+
+import (
+	"fmt"
+)
+
+// ConflictPolicy This type correspond to the behavior (byte-value) used when a download's file already exists.
+type ConflictPolicy byte
+
+const (
+	ConflictOverwrite     ConflictPolicy = iota // ConflictOverwrite replaces the existing file once the download is complete.
+	ConflictSkipIdentical                       // ConflictSkipIdentical keeps the existing file if it's identical.
+	ConflictRename                              // ConflictRename keeps the existing file, adding a suffix to the new one.
+	ConflictFail                                // ConflictFail makes the download fail with ErrFileExists.
+)
+
+// conflictPoliciesNames The names of the ConflictPolicy values, as accepted by ParseConflictPolicy.
+var conflictPoliciesNames = map[ConflictPolicy]string{
+	ConflictOverwrite:     "overwrite",
+	ConflictSkipIdentical: "skip",
+	ConflictRename:        "rename",
+	ConflictFail:          "fail",
+}
+
+// ParseConflictPolicy This function returns the ConflictPolicy with the given name ("overwrite", "skip", "rename" or "fail").
+func ParseConflictPolicy(name string) (ConflictPolicy, error) {
+	for policy, policyName := range conflictPoliciesNames {
+		if policyName == name {
+			return policy, nil
+		}
+	}
+	return ConflictOverwrite, fmt.Errorf("unknown conflict policy '%s'", name)
+}
+
+func (p ConflictPolicy) String() string {
+	return conflictPoliciesNames[p]
+}
+
+// WithConflictPolicy This function returns a RequestOption that uses the given ConflictPolicy for the download, the
+// existing file is never modified until the download is complete.
+func WithConflictPolicy(policy ConflictPolicy) RequestOption {
+	return func(options *RequestOptions) {
+		options.ConflictPolicy = policy
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrNotFound           = errors.New("not found")                    // The requested resource doesn't exist, or it's not visible.
+	ErrUnauthorized       = errors.New("unauthorized")                 // The request's credentials are missing or not valid.
+	ErrForbidden          = errors.New("forbidden")                    // The request's credentials can't access the resource.
+	ErrRateLimited        = errors.New("rate limit exceeded")          // The request was rejected due to an exceeded rate limit.
+	ErrUnexpectedStatus   = errors.New("unexpected status")            // The response's status-code is not a successful one.
+	ErrDecode             = errors.New("response decoding failed")     // The response's body couldn't be deserialized.
+	ErrNetwork            = errors.New("network failure")              // The request couldn't be made, or its response read.
+	ErrInvalidAssetUrl    = errors.New("invalid asset url")            // The asset's url doesn't belong to the configured instance.
+	ErrInvalidAssetIndex  = errors.New("invalid asset index")          // The asset's index is out of the release's assets' range.
+	ErrFileSystem         = errors.New("file system operation failed") // The downloaded content couldn't be written.
+	ErrResponseTooLarge   = errors.New("response too large")           // The response's body exceeds the maximum size.
+	ErrChecksumMismatch   = errors.New("checksum mismatch")            // The downloaded content doesn't match its checksum.
+	ErrNoChecksum         = errors.New("checksum not available")       // The asset's checksum couldn't be found for verification.
+	ErrNoSignature        = errors.New("signature not available")      // The asset's signature couldn't be found for verification.
+	ErrInvalidSignature   = errors.New("invalid signature")            // The signature doesn't match, or isn't from a trusted key.
+	ErrUnsupportedFormat  = errors.New("unsupported signature format") // The signature's format can't be verified.
+	ErrFileExists         = errors.New("file already exists")          // The download's file exists, and it can't be replaced.
+	ErrUnsupportedArchive = errors.New("unsupported archive format")   // The asset is not a supported archive.
+	ErrUnsafeArchive      = errors.New("unsafe archive entry")         // An archive's entry escapes the extraction's directory.
+	ErrNoMatchingAsset    = errors.New("no matching asset")            // No release's asset matches with the filter.
+	ErrTooManyAssets      = errors.New("too many matching assets")     // More release's assets than expected match the filter.
+	ErrAmbiguousAsset     = errors.New("ambiguous asset")              // Several release's assets match the platform equally.
+)
+
+// StatusError This error is returned when the response's status-code is not a successful one, it matches with errors.Is
+// against ErrUnexpectedStatus, and also ErrNotFound, ErrUnauthorized or ErrForbidden depending on the status-code.
+type StatusError struct {
+	Url        string
+	StatusCode int
+	Message    string // The message provided by the API's error payload, if any.
+}
+
+// NewStatusError This function creates a new StatusError for the given url's response, using the given body to read the
+// API's error message.
+func NewStatusError(url string, statusCode int, body []byte) *StatusError {
+	var payload struct {
+		Message string `json:"message"`
+	}
+	_ = json.Unmarshal(body, &payload)
+	return &StatusError{Url: url, StatusCode: statusCode, Message: payload.Message}
+}
+
+func (e *StatusError) Error() string {
+	message := fmt.Sprintf("unexpected status %d for '%s'", e.StatusCode, e.Url)
+	if e.Message != "" {
+		message += ": " + e.Message
+	}
+	return message
+}
+
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrUnexpectedStatus:
+		return true
+	case ErrNotFound:
+		return e.StatusCode == 404
+	case ErrUnauthorized:
+		return e.StatusCode == 401
+	case ErrForbidden:
+		return e.StatusCode == ForbiddenStatus
+	default:
+		return false
+	}
+}
+
+// RateLimitError This error is returned when a request is rejected by the API due to an exceeded rate limit, it matches
+// with errors.Is against ErrRateLimited.
+type RateLimitError struct {
+	Url        string
+	StatusCode int
+	RateLimit  RateLimit
+}
+
+func (e *RateLimitError) Error() string {
+	message := fmt.Sprintf("rate limit exceeded for '%s' (status %d)", e.Url, e.StatusCode)
+	if e.RateLimit.Limit > 0 {
+		message += fmt.Sprintf(", %d of %d requests remaining", max(e.RateLimit.Remaining, 0), e.RateLimit.Limit)
+	}
+	if wait := e.RateLimit.Wait(); wait > 0 {
+		message += fmt.Sprintf(", retry in %s", wait.Round(time.Second))
+	}
+	if !e.RateLimit.Reset.IsZero() {
+		message += ", resets at " + e.RateLimit.Reset.Format(time.DateTime)
+	}
+	return message
+}
+
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// DecodeError This error is returned when the response's body couldn't be deserialized into the requested model, it
+// matches with errors.Is against ErrDecode, and wraps the deserialization's error.
+type DecodeError struct {
+	Url string
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decoding response from '%s': %v", e.Url, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+func (e *DecodeError) Is(target error) bool {
+	return target == ErrDecode
+}
+
+// NetworkError This error is returned when the request couldn't be sent or its response couldn't be read, it matches with
+// errors.Is against ErrNetwork, and wraps the transport's error.
+type NetworkError struct {
+	Url string
+	Err error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("requesting '%s': %v", e.Url, e.Err)
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+func (e *NetworkError) Is(target error) bool {
+	return target == ErrNetwork
+}
+
+// ChecksumError This error is returned when a downloaded content's checksum doesn't match the expected one, it matches
+// with errors.Is against ErrChecksumMismatch.
+type ChecksumError struct {
+	Name     string
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("checksum mismatch for '%s': expected '%s', got '%s'", e.Name, e.Expected, e.Actual)
+}
+
+func (e *ChecksumError) Is(target error) bool {
+	return target == ErrChecksumMismatch
+}
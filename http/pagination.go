@@ -0,0 +1,90 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package http
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"viewer/main/common"
+)
+
+// MaxPerPage The maximum amount of items per page allowed by the API.
+const MaxPerPage = 100
+
+// PageOptions This struct holds the settings used to iterate over a paginated list endpoint.
+type PageOptions struct {
+	PerPage  int // Amount of items requested per page, up to MaxPerPage, zero uses the API's default.
+	MaxItems int // Maximum amount of items provided by the iteration, zero provides all of them.
+}
+
+// ListRequestModel This interface is used to proportionate request-method to iterate over the models provided by a GitHub
+// API's list endpoint, following the pages provided by the responses' Link header.
+type ListRequestModel[M common.RequestableModel] interface {
+	// All This method returns an iterator over all the models provided by the endpoint's pages, which are requested lazily
+	// using the given http.Client and timeout while the iteration goes on. If a page can't be requested, its error is
+	// provided and the iteration ends.
+	All(ctx context.Context, client *http.Client, timeout time.Duration, page PageOptions, options ...RequestOption) iter.Seq2[M, error]
+}
+
+// RequestAll This function realizes the same execution that ListRequestModel.All with the difference that this uses a
+// default http.Client to make the requests.
+func RequestAll[M common.RequestableModel](ctx context.Context, requestModel ListRequestModel[M], timeout time.Duration, page PageOptions, options ...RequestOption) iter.Seq2[M, error] {
+	return requestModel.All(ctx, nil, timeout, page, options...)
+}
+
+// FirstPageUrl This method returns the given url with the per_page query parameter set, if the PerPage is specified.
+func (p *PageOptions) FirstPageUrl(pageUrl string) (string, error) {
+	if p.PerPage <= 0 {
+		return pageUrl, nil
+	}
+	parsed, err := url.Parse(pageUrl)
+	if err != nil {
+		return "", err
+	}
+	query := parsed.Query()
+	query.Set("per_page", strconv.Itoa(min(p.PerPage, MaxPerPage)))
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
+
+// NextPageUrl This function returns the url with rel="next" provided by the given headers' Link header, or an empty string
+// if there is no next page.
+func NextPageUrl(header http.Header) string {
+	for _, link := range strings.Split(header.Get("Link"), ",") {
+		target, params, found := strings.Cut(link, ";")
+		if !found {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if name == "rel" && strings.Trim(value, `"`) == "next" {
+				return strings.Trim(strings.TrimSpace(target), "<>")
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package http
+
+import (
+	"time"
+)
+
+// Progress This struct represents the state of a download at some moment, it's provided to the ProgressObserver specified
+// with the WithProgress option.
+type Progress struct {
+	Name     string        // The downloaded file's name.
+	Done     int64         // The amount of bytes available, including the ones of a resumed download.
+	Total    int64         // The content's complete size, or -1 if it's unknown.
+	Offset   int64         // The amount of bytes that were already available when the download was resumed.
+	Elapsed  time.Duration // The time elapsed since the download started.
+	Finished bool          // Whether the download has finished, successfully or not.
+}
+
+// Rate This method returns the download's average rate in bytes per second, excluding the bytes of a resumed download.
+func (p Progress) Rate() float64 {
+	if p.Elapsed <= 0 {
+		return 0
+	}
+	return float64(p.Done-p.Offset) / p.Elapsed.Seconds()
+}
+
+// ETA This method returns the estimated time until the download is complete, or -1 if the content's size or the rate are
+// unknown.
+func (p Progress) ETA() time.Duration {
+	rate := p.Rate()
+	if p.Total < 0 || rate <= 0 {
+		return -1
+	}
+	return time.Duration(float64(p.Total-p.Done) / rate * float64(time.Second))
+}
+
+// Percent This method returns the download's completed percentage, or -1 if the content's size is unknown.
+func (p Progress) Percent() float64 {
+	if p.Total < 0 {
+		return -1
+	}
+	if p.Total == 0 {
+		return 100
+	}
+	return float64(p.Done) / float64(p.Total) * 100
+}
+
+// ProgressObserver This interface is used to receive the downloads' progress, which is provided periodically while the
+// content is written, and once more when the download finishes. The observer may be called from multiple goroutines, but
+// never concurrently for the same download.
+type ProgressObserver interface {
+	// Progress This method receives the current Progress of a download.
+	Progress(progress Progress)
+}
+
+// ProgressFunc This type allows the use of ordinary functions as a ProgressObserver.
+type ProgressFunc func(progress Progress)
+
+// Progress This method calls the function with the given Progress.
+func (f ProgressFunc) Progress(progress Progress) {
+	f(progress)
+}
+
+// ProgressInterval The minimum time between two Progress notifications of the same download.
+const ProgressInterval = 200 * time.Millisecond
+
+// WithProgress This function returns a RequestOption that provides the downloads' Progress to the given ProgressObserver.
+func WithProgress(observer ProgressObserver) RequestOption {
+	return func(options *RequestOptions) {
+		options.Progress = observer
+	}
+}
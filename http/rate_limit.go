@@ -0,0 +1,104 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimitPolicy This type correspond to the behavior (byte-value) used when a request exceeds the API's rate limit.
+type RateLimitPolicy byte
+
+const (
+	RateLimitFail RateLimitPolicy = iota // RateLimitFail makes the request fail with a RateLimitError.
+	RateLimitWait                        // RateLimitWait sleeps until the rate limit is reset and then repeats the request.
+)
+
+const (
+	DefaultMaxRateLimitWait = 15 * time.Minute // Longest wait accepted by the RateLimitWait policy by default.
+	TooManyRequestsStatus   = 429              // Status-code used by the API for some exceeded rate limits.
+	ForbiddenStatus         = 403              // Status-code used by the API for exceeded primary rate limits.
+)
+
+// RateLimit This struct represents the rate-limit information provided by the API's response headers.
+type RateLimit struct {
+	Limit      int           // Maximum amount of requests allowed per window, zero if the header is not present.
+	Remaining  int           // Amount of requests remaining in the current window, -1 if the header is not present.
+	Used       int           // Amount of requests made in the current window.
+	Reset      time.Time     // Time when the current window is reset, zero if the header is not present.
+	RetryAfter time.Duration // Time requested by the API to wait before retrying, zero if the header is not present.
+}
+
+// RateLimitFrom This function reads the X-RateLimit-* and Retry-After headers from the given http.Header.
+func RateLimitFrom(header http.Header) RateLimit {
+	rateLimit := RateLimit{Remaining: -1}
+	if limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit")); err == nil {
+		rateLimit.Limit = limit
+	}
+	if remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining")); err == nil {
+		rateLimit.Remaining = remaining
+	}
+	if used, err := strconv.Atoi(header.Get("X-RateLimit-Used")); err == nil {
+		rateLimit.Used = used
+	}
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rateLimit.Reset = time.Unix(reset, 0)
+	}
+	if retryAfter := header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			rateLimit.RetryAfter = time.Duration(seconds) * time.Second
+		} else if date, err := http.ParseTime(retryAfter); err == nil {
+			rateLimit.RetryAfter = time.Until(date)
+		}
+	}
+	return rateLimit
+}
+
+// Exceeded This method returns whether the headers indicate that no more requests can be made until the Wait duration
+// elapses.
+func (r *RateLimit) Exceeded() bool {
+	return r.Remaining == 0 || r.RetryAfter > 0
+}
+
+// Wait This method returns the duration to wait before the next request is allowed, the Retry-After's value has priority
+// over the window's reset time.
+func (r *RateLimit) Wait() time.Duration {
+	if r.RetryAfter > 0 {
+		return r.RetryAfter
+	}
+	if r.Reset.IsZero() {
+		return 0
+	}
+	// The reset time has a precision of seconds, so wait an extra second to avoid requesting too early.
+	return max(time.Until(r.Reset)+time.Second, 0)
+}
+
+// RateLimited This function returns whether the given status-code and rate-limit information correspond to a response
+// rejected due to an exceeded rate limit.
+func RateLimited(statusCode int, rateLimit *RateLimit) bool {
+	if statusCode == TooManyRequestsStatus {
+		return true
+	}
+	return statusCode == ForbiddenStatus && rateLimit.Exceeded()
+}
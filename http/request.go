@@ -22,6 +22,7 @@
 package http
 
 import (
+	"context"
 	"net/http"
 	"time"
 	"viewer/main/common"
@@ -39,23 +40,141 @@ const ResponseOkStatus = 200
 // functions with the received information, such as, check or download content.
 type RequestModel[M common.RequestableModel] interface {
 	// RequestWith This method request to the URL using the given http.Client and the specified timeout to return the model
-	// with the requested information if it is available.
-	RequestWith(client *http.Client, timeout time.Duration) *M
+	// with the requested information if it is available, otherwise an error that can be inspected with errors.Is against
+	// the package's errors, such as ErrNotFound, ErrRateLimited or ErrDecode.
+	RequestWith(client *http.Client, timeout time.Duration, options ...RequestOption) (*M, error)
 
 	// RequestWithAndThen This method request to the URL using the given http.Client and timeout to provide the model
 	// (if it is available) with the requested information. Also, if the model is available, it will be used to execute the
-	// specified consumer's logic.
-	RequestWithAndThen(client *http.Client, consumer func(*M), timeout time.Duration) *M
+	// specified consumer's logic, which is not executed when an error is returned.
+	RequestWithAndThen(client *http.Client, consumer func(*M), timeout time.Duration, options ...RequestOption) (*M, error)
+
+	// RequestWithContext This method realizes the same execution that RequestWith, the request is aborted when the given
+	// context is done.
+	RequestWithContext(ctx context.Context, client *http.Client, timeout time.Duration, options ...RequestOption) (*M, error)
+
+	// RequestWithAndThenContext This method realizes the same execution that RequestWithAndThen, the request is aborted when
+	// the given context is done.
+	RequestWithAndThenContext(ctx context.Context, client *http.Client, consumer func(*M), timeout time.Duration, options ...RequestOption) (*M, error)
+}
+
+// RequestOptions This struct holds the settings used for a single request, by default they're taken from the DefaultConfig.
+type RequestOptions struct {
+	Retry            RetryPolicy
+	RateLimitPolicy  RateLimitPolicy
+	MaxRateLimitWait time.Duration
+	MaxResponseSize  int64
+	Header           http.Header
+	Segments         int
+	Progress         ProgressObserver
+	Checksum         string
+	Verify           bool
+	TrustedKeys      []string
+	ConflictPolicy   ConflictPolicy
+	BandwidthLimit   int64
+	Validator        func(path string) error
+}
+
+// RequestOption This type correspond to a function that modifies the RequestOptions used for a request.
+type RequestOption func(*RequestOptions)
+
+// WithRetry This function returns a RequestOption that uses the given RetryPolicy for the request.
+func WithRetry(policy RetryPolicy) RequestOption {
+	return func(options *RequestOptions) {
+		options.Retry = policy
+	}
+}
+
+// WithRateLimitPolicy This function returns a RequestOption that uses the given RateLimitPolicy and maximum wait for the
+// request.
+func WithRateLimitPolicy(policy RateLimitPolicy, maxWait time.Duration) RequestOption {
+	return func(options *RequestOptions) {
+		options.RateLimitPolicy = policy
+		options.MaxRateLimitWait = maxWait
+	}
+}
+
+// WithMaxResponseSize This function returns a RequestOption that limits the response's body to the given size, a value
+// that is not positive disables the limit.
+func WithMaxResponseSize(size int64) RequestOption {
+	return func(options *RequestOptions) {
+		options.MaxResponseSize = size
+	}
+}
+
+// WithSegments This function returns a RequestOption that splits the downloads into the given amount of byte ranges, which
+// are requested concurrently, a value lower than two downloads the content with a single connection, as it's done when
+// the server doesn't support ranges or the content is too small.
+func WithSegments(segments int) RequestOption {
+	return func(options *RequestOptions) {
+		options.Segments = segments
+	}
+}
+
+// WithChecksum This function returns a RequestOption that verifies the downloaded content against the given checksum, which
+// looks like "sha256:<hex-digest>", as the API's assets' digests. The download fails with a ChecksumError if it doesn't
+// match.
+func WithChecksum(checksum string) RequestOption {
+	return func(options *RequestOptions) {
+		options.Checksum = checksum
+	}
+}
+
+// WithVerify This function returns a RequestOption that enables or disables the verification of the releases' assets'
+// downloads, using the checksums provided by the API or by the release's checksum assets.
+func WithVerify(verify bool) RequestOption {
+	return func(options *RequestOptions) {
+		options.Verify = verify
+	}
+}
+
+// WithTrustedKeys This function returns a RequestOption that requires the releases' assets' downloads to be signed by one
+// of the given public keys, instead of the ones trusted for the release's repository by the DefaultConfig.
+func WithTrustedKeys(keys ...string) RequestOption {
+	return func(options *RequestOptions) {
+		options.TrustedKeys = keys
+	}
+}
+
+// WithValidator This function returns a RequestOption that validates the downloads' content with the given function before
+// they're committed, or the existing file when an identical one is skipped, which receives the path of the file to
+// validate. The download fails with the function's error, and its part-file is removed, if the content is not valid.
+func WithValidator(validator func(path string) error) RequestOption {
+	return func(options *RequestOptions) {
+		options.Validator = validator
+	}
+}
+
+// WithHeader This function returns a RequestOption that adds the given header to the request, such as a Range header.
+func WithHeader(name string, value string) RequestOption {
+	return func(options *RequestOptions) {
+		if options.Header == nil {
+			options.Header = http.Header{}
+		}
+		options.Header.Add(name, value)
+	}
 }
 
 // Request This function realizes the same execution that RequestAndThen with the difference that this uses a default
 // http.Client to make the request.
-func Request[M common.RequestableModel](requestModel RequestModel[M], timeout time.Duration) *M {
-	return requestModel.RequestWith(nil, timeout)
+func Request[M common.RequestableModel](requestModel RequestModel[M], timeout time.Duration, options ...RequestOption) (*M, error) {
+	return requestModel.RequestWith(nil, timeout, options...)
 }
 
 // RequestAndThen This function realizes the same execution that RequestWithAndThen with the difference that this uses a
 // default http.Client to make the request.
-func RequestAndThen[M common.RequestableModel](requestModel RequestModel[M], consumer func(*M), timeout time.Duration) *M {
-	return requestModel.RequestWithAndThen(nil, consumer, timeout)
+func RequestAndThen[M common.RequestableModel](requestModel RequestModel[M], consumer func(*M), timeout time.Duration, options ...RequestOption) (*M, error) {
+	return requestModel.RequestWithAndThen(nil, consumer, timeout, options...)
+}
+
+// RequestContext This function realizes the same execution that Request, the request is aborted when the given context is
+// done.
+func RequestContext[M common.RequestableModel](ctx context.Context, requestModel RequestModel[M], timeout time.Duration, options ...RequestOption) (*M, error) {
+	return requestModel.RequestWithContext(ctx, nil, timeout, options...)
+}
+
+// RequestAndThenContext This function realizes the same execution that RequestAndThen, the request is aborted when the
+// given context is done.
+func RequestAndThenContext[M common.RequestableModel](ctx context.Context, requestModel RequestModel[M], consumer func(*M), timeout time.Duration, options ...RequestOption) (*M, error) {
+	return requestModel.RequestWithAndThenContext(ctx, nil, consumer, timeout, options...)
 }
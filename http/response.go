@@ -21,11 +21,135 @@
 
 package http
 
-import "io"
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
 
-// ResponseModel This struct represents a provided response's main information, such as Body, body as json-text, and status-code.
+// DefaultMaxResponseSize The maximum size of the responses' bodies read by default, bigger bodies fail with ErrResponseTooLarge.
+const DefaultMaxResponseSize = 32 << 20
+
+// drainLimit The maximum amount of unread bytes discarded when a ResponseModel is closed, so the connection can be reused.
+const drainLimit = 4 << 10
+
+// ResponseModel This struct represents a provided response's main information, such as status-code, headers, the rate-limit
+// information provided by them, and the final url after any redirect. The body is not buffered, it can be read lazily using
+// Body, Bytes or DecodeJSON, and it must be closed by the caller. Cached is true when the body is served from the
+// responses' cache after a successful revalidation.
 type ResponseModel struct {
-	JSON       string
 	StatusCode int
-	Body       io.ReadCloser
+	Header     http.Header
+	Url        string
+	RateLimit  RateLimit
+	Cached     bool
+	body       *responseBody
+}
+
+// Timing This struct provides the timing information of a response.
+type Timing struct {
+	Start   time.Time     // Time when the request was sent.
+	Headers time.Duration // Time elapsed until the response's headers were received.
+	Total   time.Duration // Time elapsed until the body was completely read or closed, zero while it's being read.
+}
+
+// NewResponseModel This function creates a new ResponseModel with the given information, the given body is read up to the
+// given maximum size (unlimited if it's not positive), and the timing is measured from the given start time.
+func NewResponseModel(statusCode int, header http.Header, url string, body io.ReadCloser, start time.Time, maxSize int64) *ResponseModel {
+	return &ResponseModel{
+		StatusCode: statusCode,
+		Header:     header,
+		Url:        url,
+		RateLimit:  RateLimitFrom(header),
+		body: &responseBody{
+			reader:    body,
+			limited:   maxSize > 0,
+			maxSize:   maxSize,
+			remaining: maxSize,
+			start:     start,
+			headers:   time.Since(start),
+		},
+	}
+}
+
+// Body This method returns the response's body, which fails with ErrResponseTooLarge if the maximum size is exceeded.
+func (r *ResponseModel) Body() io.ReadCloser {
+	return r.body
+}
+
+// Bytes This method reads the whole response's body and closes it.
+func (r *ResponseModel) Bytes() ([]byte, error) {
+	defer func(r *ResponseModel) {
+		_ = r.Close()
+	}(r)
+	return io.ReadAll(r.body)
+}
+
+// DecodeJSON This method deserializes the response's json-stream into the given value and closes the body.
+func (r *ResponseModel) DecodeJSON(value any) error {
+	defer func(r *ResponseModel) {
+		_ = r.Close()
+	}(r)
+	return json.NewDecoder(r.body).Decode(value)
+}
+
+// Close This method closes the response's body, discarding a few unread bytes (like the trailing new-line after a json
+// value) first, so the connection can be reused.
+func (r *ResponseModel) Close() error {
+	_, _ = io.CopyN(io.Discard, r.body, drainLimit)
+	return r.body.Close()
+}
+
+// Timing This method returns the response's Timing information.
+func (r *ResponseModel) Timing() Timing {
+	timing := Timing{Start: r.body.start, Headers: r.body.headers}
+	if !r.body.end.IsZero() {
+		timing.Total = r.body.end.Sub(r.body.start)
+	}
+	return timing
+}
+
+// responseBody This struct wraps a response's body to limit its size, and to register when it was read completely.
+type responseBody struct {
+	reader    io.ReadCloser
+	limited   bool
+	maxSize   int64
+	remaining int64
+	start     time.Time
+	headers   time.Duration
+	end       time.Time
+}
+
+func (b *responseBody) Read(p []byte) (int, error) {
+	// Allow one byte more than the remaining ones to detect when the body exceeds the maximum size.
+	if b.limited && int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.reader.Read(p)
+	if b.limited {
+		if int64(n) > b.remaining {
+			n = int(b.remaining)
+			b.remaining = 0
+			b.finish()
+			return n, fmt.Errorf("%w: exceeds %d bytes", ErrResponseTooLarge, b.maxSize)
+		}
+		b.remaining -= int64(n)
+	}
+	if err != nil {
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *responseBody) Close() error {
+	b.finish()
+	return b.reader.Close()
+}
+
+func (b *responseBody) finish() {
+	if b.end.IsZero() {
+		b.end = time.Now()
+	}
 }
@@ -0,0 +1,71 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package http
+
+import (
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// RetryPolicy This struct defines how many times and how often a request failed due to a network error or a transient
+// status-code is repeated.
+type RetryPolicy struct {
+	MaxAttempts     int           // Total amount of attempts, including the first one, values below 2 disable the retries.
+	BaseDelay       time.Duration // Delay used for the first retry, doubled for every following retry.
+	MaxDelay        time.Duration // Maximum delay between attempts, Retry-After values above it are not honored.
+	RetryableStatus []int         // Status-codes considered transient.
+}
+
+// DefaultRetryPolicy The RetryPolicy used by the DefaultConfig, it retries up to two times on server errors.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:     3,
+	BaseDelay:       500 * time.Millisecond,
+	MaxDelay:        10 * time.Second,
+	RetryableStatus: []int{408, 500, 502, 503, 504},
+}
+
+// NoRetryPolicy A RetryPolicy that never repeats a request.
+var NoRetryPolicy = RetryPolicy{MaxAttempts: 1}
+
+// Retryable This method returns whether a response with the given status-code should be retried.
+func (p *RetryPolicy) Retryable(statusCode int) bool {
+	return slices.Contains(p.RetryableStatus, statusCode)
+}
+
+// Delay This method returns the delay before the retry following the given failed attempt (starting from 1), using an
+// exponential backoff with full jitter, or the given Retry-After's value when it's longer. The returned boolean is false
+// when no more attempts are allowed, or the Retry-After's value exceeds the MaxDelay.
+func (p *RetryPolicy) Delay(attempt int, retryAfter time.Duration) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || retryAfter > p.MaxDelay {
+		return 0, false
+	}
+	backoff := p.MaxDelay
+	if shift := attempt - 1; shift < 32 && p.BaseDelay<<shift < p.MaxDelay {
+		backoff = p.BaseDelay << shift
+	}
+	var delay time.Duration
+	if backoff > 0 {
+		delay = rand.N(backoff + 1)
+	}
+	return max(delay, retryAfter), true
+}
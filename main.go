@@ -22,10 +22,22 @@
 package main
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"os/signal"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+	"viewer/main/async"
+	"viewer/main/cache"
 	"viewer/main/download"
 	"viewer/main/http"
 	"viewer/main/repository"
@@ -40,82 +52,507 @@ func showArgumentsUsage() {
 	fmt.Println("[*] You can get repository's latest release by specifying 'latest' word.")
 	fmt.Println(" - gvw <user> <repository> <release>")
 	fmt.Println("To download assets from a published release, arguments should look like this:")
-	fmt.Println("[*] Index parameter should look like this '*' if you want to download all assets,\notherwise you must specify the asset's index.")
+	fmt.Println("[*] Index parameter should look like this '*' if you want to download all assets,\notherwise you must specify the asset's index,")
+	fmt.Println("    a glob pattern of its name like '*linux*.tar.gz', or a regular expression like 're:^app-.*\\.zip$'.")
+	fmt.Println("[*] Index parameter can also be 'auto' to download the asset built for this platform, or for the one given by -platform.")
 	fmt.Println("[*] If you want to download the files at the current directory, let the parameter empty using double quotes.")
 	fmt.Println(" - gvw <user> <repository> <release> <index> <directory>")
+	fmt.Println("[*] To write a single asset into the standard output (e.g. for 'tar -x'), use -o - and omit the directory.")
+	fmt.Println(" - gvw -o - <user> <repository> <release> <index>")
 	fmt.Println()
 	fmt.Println("Example: - gvw aivruu repo-viewer latest * [you must use double quotes here to let it empty]")
 	fmt.Println()
+	fmt.Println("To list a repository's releases or tags, arguments should look like this:")
+	fmt.Println(" - gvw -list releases <user> <repository>")
+	fmt.Println(" - gvw -list tags <user> <repository>")
+	fmt.Println("To check the API's rate-limit status, arguments should look like this:")
+	fmt.Println(" - gvw rate-limit")
+	fmt.Println("To inspect or remove the cached API's responses, arguments should look like this:")
+	fmt.Println(" - gvw cache")
+	fmt.Println(" - gvw cache-purge")
+	fmt.Println()
+	fmt.Println("Options (must be specified before the arguments):")
+	flag.PrintDefaults()
+	fmt.Println()
 }
 
 func main() {
-	argsAmount := len(os.Args)
-	if argsAmount < 2 || argsAmount > 6 {
+	// The exit code is set by the failed downloads, and used once the other deferred functions are executed.
+	exitCode := 0
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
+	apiUrl := flag.String("api-url", "", "GitHub API base URL, e.g. https://github.example.com/api/v3 (env: "+http.BaseUrlEnv+")")
+	token := flag.String("token", "", "token used to authenticate the requests (env: "+http.TokenEnv+" or "+http.ActionsTokenEnv+")")
+	waitRateLimit := flag.Bool("wait-rate-limit", false, "wait until the rate limit is reset instead of failing when it is exceeded")
+	maxRateLimitWait := flag.Duration("max-rate-limit-wait", http.DefaultMaxRateLimitWait, "longest wait accepted by -wait-rate-limit")
+	retries := flag.Int("retries", http.DefaultRetryPolicy.MaxAttempts-1, "times a request is retried on network errors or server errors")
+	noCache := flag.Bool("no-cache", false, "don't cache nor revalidate the API's responses")
+	list := flag.String("list", "", "list the repository's 'releases' or 'tags' instead of showing its information")
+	perPage := flag.Int("per-page", http.MaxPerPage, "items requested per page by -list")
+	maxItems := flag.Int("max-items", 0, "maximum amount of items shown by -list, 0 shows all of them")
+	verify := flag.Bool("verify", false, "verify the downloaded assets against the release's checksums, failing if there are none")
+	flag.Func("trusted-key", "pin a public key (minisign or base64 ed25519) for a repository as 'owner/name=key', can be repeated",
+		func(value string) error {
+			name, key, found := strings.Cut(value, "=")
+			if !found || !strings.Contains(name, "/") || key == "" {
+				return errors.New("expected 'owner/name=key'")
+			}
+			http.DefaultConfig.TrustKey(name, key)
+			return nil
+		})
+	onConflict := flag.String("on-conflict", http.ConflictOverwrite.String(), "behavior when a downloaded file exists: 'overwrite', 'skip' (if identical), 'rename' or 'fail'")
+	extract := flag.Bool("extract", false, "extract the downloaded zip, tar, tar.gz and tar.bz2 assets into the directory")
+	stripComponents := flag.Int("strip-components", 0, "leading path components removed from the entries extracted by -extract")
+	include := flag.String("include", "", "comma-separated patterns of the entries extracted by -extract, e.g. 'bin/*,LICENSE'")
+	exclude := flag.String("exclude", "", "comma-separated glob patterns of the assets never downloaded, e.g. '*.sha256,*.sig'")
+	contentType := flag.String("content-type", "", "content-type of the downloaded assets, e.g. 'application/gzip'")
+	maxMatches := flag.Int("max-matches", 1, "maximum amount of assets a glob or regex selector can match, 0 for unlimited")
+	platform := flag.String("platform", "", "platform used by the 'auto' selector as 'os/arch' or 'os/arch/libc', e.g. 'linux/arm64/musl' (default the current one)")
+	parallel := flag.Int("parallel", 4, "maximum amount of assets downloaded at the same time")
+	limitRate := flag.String("limit-rate", "0", "maximum bandwidth used by all the downloads together, e.g. '5M' or '500K', 0 for unlimited")
+	limitRateEach := flag.String("limit-rate-each", "0", "maximum bandwidth used by each download, e.g. '1M', 0 for unlimited")
+	output := flag.String("o", "", "write the selected asset into this file instead of the directory, '-' for the standard output")
+	segments := flag.Int("segments", 1, "concurrent connections used to download each asset, when the server supports ranges")
+	flag.Usage = showArgumentsUsage
+	flag.Parse()
+	if *apiUrl != "" {
+		if err := http.DefaultConfig.SetBaseUrl(*apiUrl); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	if *token != "" {
+		http.DefaultConfig.Token = *token
+	}
+	if *waitRateLimit {
+		http.DefaultConfig.RateLimitPolicy = http.RateLimitWait
+	}
+	http.DefaultConfig.MaxRateLimitWait = *maxRateLimitWait
+	http.DefaultConfig.Retry.MaxAttempts = *retries + 1
+	http.DefaultConfig.Segments = *segments
+	http.DefaultConfig.Verify = *verify
+	conflictPolicy, err := http.ParseConflictPolicy(*onConflict)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	http.DefaultConfig.ConflictPolicy = conflictPolicy
+	if http.DefaultConfig.BandwidthLimit, err = http.ParseBandwidth(*limitRate); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if http.DefaultConfig.DownloadBandwidthLimit, err = http.ParseBandwidth(*limitRateEach); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if store, err := cache.NewDefaultStore(); err == nil && !*noCache {
+		http.DefaultConfig.Cache = store
+	}
+	// Cancel the in-flight requests and downloads on Ctrl-C, so partial files are removed or kept to be resumed.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	args := flag.Args()
+	argsAmount := len(args)
+	if argsAmount == 1 && (args[0] == "cache" || args[0] == "cache-purge") {
+		runCacheCommand(args[0])
+		return
+	}
+	if argsAmount == 1 && args[0] == "rate-limit" {
+		model, err := http.RequestContext(ctx, repository.NewRateLimitRequest(http.DefaultConfig.RateLimitUrl()), 5)
+		if err != nil {
+			fmt.Println("Failed to request the rate-limit status:", describeError(err))
+			return
+		}
+		printRateLimitInformation(model)
+		return
+	}
+	if argsAmount < 2 || argsAmount > 5 {
 		showArgumentsUsage()
 		return
 	}
-	if (argsAmount == 6) && (strings.Contains(os.Args[3], ".") || strings.Contains(os.Args[3], "latest")) {
-		releaseRequest := repository.NewReleaseRequest(ForRelease(os.Args[1], os.Args[2], os.Args[3]))
-		model := http.Request(releaseRequest, 5)
-		if model == nil {
-			fmt.Println("Failed to request the release for asset download.")
+	if *list != "" {
+		if argsAmount != 2 {
+			showArgumentsUsage()
 			return
 		}
-		if os.Args[4] == "*" {
-			fmt.Println("Downloading all release's assets...")
-			downloadAllAssets(os.Args[5], model)
-		} else {
-			index, err := strconv.Atoi(os.Args[4])
+		listModels(ctx, *list, args[0], args[1], http.PageOptions{PerPage: *perPage, MaxItems: *maxItems})
+		return
+	}
+	// The directory is not used when the asset is written into the output.
+	if (argsAmount == 5 || (argsAmount == 4 && *output != "")) && (strings.Contains(args[2], ".") || strings.Contains(args[2], "latest")) {
+		// The standard output may be the asset's content, so the messages are written into the standard error.
+		messages := io.Writer(os.Stdout)
+		if *output == "-" {
+			messages = os.Stderr
+		}
+		releaseRequest := repository.NewReleaseRequest(ForRelease(args[0], args[1], args[2]))
+		model, err := http.RequestContext(ctx, releaseRequest, 5)
+		if err != nil {
+			_, _ = fmt.Fprintln(messages, "Failed to request the release for asset download:", describeError(err))
+			exitCode = 1
+			return
+		}
+		if *output != "" {
+			if *extract {
+				_, _ = fmt.Fprintln(messages, "The -extract option can't be used with -o.")
+				exitCode = 1
+				return
+			}
+			index, err := selectAsset(messages, model, args[3], assetFilter(args[3], *exclude, *contentType, 1), *platform)
 			if err != nil {
-				fmt.Println("Not valid index-value for asset download.")
+				_, _ = fmt.Fprintln(messages, "No asset can be written:", describeError(err))
+				exitCode = 1
 				return
 			}
-			downloadAsset(os.Args[5], model, index-1)
+			if err := writeAsset(ctx, *output, model, index, newProgressPrinter(os.Stderr, 1)); err != nil {
+				exitCode = 1
+			}
+			return
 		}
-		return
-	}
-	if argsAmount == 4 {
-		var releaseRequest *repository.RequestReleaseModelImpl
-		if os.Args[3] == "latest" {
-			releaseRequest = repository.NewReleaseRequest(ForRelease(os.Args[1], os.Args[2], "latest"))
-		} else {
-			releaseRequest = repository.NewReleaseRequest(ForRelease(os.Args[1], os.Args[2], os.Args[3]))
+		var extractOptions *download.ExtractOptions
+		if *extract {
+			extractOptions = &download.ExtractOptions{StripComponents: *stripComponents}
+			if *include != "" {
+				extractOptions.Include = strings.Split(*include, ",")
+			}
 		}
-		if releaseRequest == nil {
-			fmt.Println("Failed to create the request.")
+		if index, err := strconv.Atoi(args[3]); err == nil {
+			if report := downloadAsset(ctx, args[4], model, index-1, extractOptions, newProgressPrinter(os.Stdout, 1)); report.Err != nil {
+				exitCode = 1
+			}
 			return
 		}
-		model := http.Request(releaseRequest, 5)
-		if model == nil {
-			fmt.Println("Failed to request the release for this repository.")
+		if args[3] == "auto" {
+			index, err := selectPlatformAsset(os.Stdout, model, *platform)
+			if err != nil {
+				fmt.Println("No asset can be downloaded:", describeError(err))
+				exitCode = 1
+				return
+			}
+			if report := downloadAsset(ctx, args[4], model, index, extractOptions, newProgressPrinter(os.Stdout, 1)); report.Err != nil {
+				exitCode = 1
+			}
+			return
+		}
+		filter := assetFilter(args[3], *exclude, *contentType, *maxMatches)
+		indexes, err := model.Filter(filter)
+		if err != nil {
+			fmt.Println("No asset can be downloaded:", describeError(err))
+			exitCode = 1
+			return
+		}
+		fmt.Printf("Downloading %d release's assets...\n", len(indexes))
+		reports := downloadAssets(ctx, args[4], model, indexes, extractOptions, *parallel)
+		fmt.Println()
+		if printSummary(os.Stdout, reports) > 0 {
+			exitCode = 1
+		}
+		return
+	}
+	if argsAmount == 3 {
+		releaseRequest := repository.NewReleaseRequest(ForRelease(args[0], args[1], args[2]))
+		model, err := http.RequestContext(ctx, releaseRequest, 5)
+		if err != nil {
+			fmt.Println("Failed to request the release for this repository:", describeError(err))
 			return
 		}
 		printReleaseInformation(model)
 		return
 	}
-	repositoryRequest := repository.NewRepositoryRequest(ForRepository(os.Args[1], os.Args[2]))
-	model := http.Request(repositoryRequest, 5)
-	if model == nil {
-		fmt.Println("Failed to request the repository.")
+	repositoryRequest := repository.NewRepositoryRequest(ForRepository(args[0], args[1]))
+	model, err := http.RequestContext(ctx, repositoryRequest, 5)
+	if err != nil {
+		fmt.Println("Failed to request the repository:", describeError(err))
 		return
 	}
 	printRepositoryInformation(model)
 }
 
-func downloadAsset(directory string, model *repository.GithubReleaseModel, index int) {
-	read := model.Download(directory, index)
-	fmt.Println("Downloading asset...")
-	if read == download.InvalidAssetDefaultSize || read == download.UnknownAssetDefaultSize {
-		fmt.Printf("This asset couldn't be downloaded, may be due to an out of range value, index '%d' assets-amount '%d'", index, len(model.Assets))
+func listModels(ctx context.Context, list string, author string, name string, page http.PageOptions) {
+	switch list {
+	case "releases":
+		fmt.Println("Showing releases for repository:", name)
+		for release, err := range http.RequestAll(ctx, repository.NewReleasesRequest(http.DefaultConfig.ReleasesUrl(author, name)), 5, page) {
+			if err != nil {
+				fmt.Println("Failed to request the releases:", describeError(err))
+				return
+			}
+			fmt.Printf("  %s -> %s (%d assets)\n", release.TagName, release.Name, len(release.Assets))
+		}
+	case "tags":
+		fmt.Println("Showing tags for repository:", name)
+		for tag, err := range http.RequestAll(ctx, repository.NewTagsRequest(http.DefaultConfig.TagsUrl(author, name)), 5, page) {
+			if err != nil {
+				fmt.Println("Failed to request the tags:", describeError(err))
+				return
+			}
+			fmt.Printf("  %s -> %s\n", tag.Name, tag.Commit.Sha)
+		}
+	default:
+		fmt.Printf("Unknown list '%s', it should be 'releases' or 'tags'.\n", list)
+	}
+}
+
+func runCacheCommand(command string) {
+	store, err := cache.NewDefaultStore()
+	if err != nil {
+		fmt.Println("Failed to locate the cache directory: ", err)
 		return
 	}
-	fmt.Printf("Downloaded asset with name '%s' and '%d' read bytes. ", model.Assets[index].Name, read)
+	if command == "cache-purge" {
+		removed, err := store.Purge()
+		if err != nil {
+			fmt.Println("Failed to purge the cache: ", err)
+		}
+		fmt.Printf("Removed %d cached responses from '%s'.\n", removed, store.Directory())
+		return
+	}
+	entries, err := store.Entries()
+	if err != nil {
+		fmt.Println("Failed to read the cache: ", err)
+		return
+	}
+	fmt.Printf("Showing %d cached responses from '%s':\n", len(entries), store.Directory())
+	for _, entry := range entries {
+		fmt.Println()
+		fmt.Println("  URL ->", entry.Url)
+		fmt.Println("  Size ->", len(entry.Body), "bytes")
+		fmt.Println("  Stored ->", entry.StoredAt.Format(time.DateTime))
+		if entry.ETag != "" {
+			fmt.Println("  ETag ->", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			fmt.Println("  Last-Modified ->", entry.LastModified)
+		}
+	}
+}
+
+// downloadAsset This function downloads the release's asset with the given index into the directory, extracting it if the
+// extraction's options are given and the asset is an archive, and returns the download's report.
+func downloadAsset(ctx context.Context, directory string, model *repository.GithubReleaseModel, index int, extract *download.ExtractOptions, printer *progressPrinter) assetReport {
+	start := time.Now()
+	name := fmt.Sprintf("#%d", index+1)
+	if index >= 0 && index < len(model.Assets) {
+		name = model.Assets[index].Name
+		if _, archive := download.FormatOf(name); archive && extract != nil {
+			files, err := model.DownloadAndExtractContext(ctx, directory, index, *extract, http.WithProgress(printer))
+			if err != nil {
+				printer.Finish("This asset couldn't be downloaded and extracted:", describeError(err))
+				return failedReport(name, time.Since(start), err)
+			}
+			printer.Finish(fmt.Sprintf("Downloaded asset with name '%s' and extracted %d files.", name, len(files)))
+			return assetReport{Name: name, Bytes: model.Assets[index].Size, Duration: time.Since(start),
+				Status: fmt.Sprintf("extracted %d files", len(files))}
+		}
+	}
+	result, err := model.DownloadContext(ctx, directory, index, http.WithProgress(printer))
+	if err != nil {
+		printer.Finish("This asset couldn't be downloaded:", describeError(err))
+		return failedReport(name, result.Duration, err)
+	}
+	switch {
+	case result.Unknown():
+		printer.Finish(fmt.Sprintf("The asset with name '%s' is empty.", name))
+	case result.Skipped():
+		printer.Finish(fmt.Sprintf("Skipped asset with name '%s', as an identical file exists at '%s'.", name, result.Path))
+	default:
+		printer.Finish(fmt.Sprintf("Downloaded asset with name '%s' and '%d' read bytes (sha256:%s).", name, result.Bytes, result.Sha256))
+	}
+	return assetReport{Name: name, Bytes: result.Bytes, Duration: result.Duration, Status: result.Status.String()}
+}
+
+// describeError This function returns the given error's message, including a hint about how to solve it when possible.
+func describeError(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "cancelled"
+	case errors.Is(err, http.ErrRateLimited):
+		return err.Error() + " (use -token to increase the limit, or -wait-rate-limit to wait for its reset)"
+	case errors.Is(err, http.ErrNotFound) && http.DefaultConfig.Token == "":
+		return err.Error() + " (private repositories require a token)"
+	case errors.Is(err, http.ErrNoChecksum):
+		return err.Error() + " (the release doesn't provide checksums for it, download it without -verify)"
+	case errors.Is(err, http.ErrChecksumMismatch):
+		return err.Error() + " (the file was removed, as it may be corrupted or tampered)"
+	case errors.Is(err, http.ErrNoSignature), errors.Is(err, http.ErrInvalidSignature), errors.Is(err, http.ErrUnsupportedFormat):
+		return err.Error() + " (the file was removed, as the repository's keys are pinned with -trusted-key)"
+	case errors.Is(err, http.ErrFileExists):
+		return err.Error() + " (use -on-conflict to overwrite, skip or rename it)"
+	case errors.Is(err, http.ErrUnsafeArchive):
+		return err.Error() + " (the archive may be malicious, the extraction was stopped)"
+	case errors.Is(err, http.ErrTooManyAssets):
+		return err.Error() + " (use a more specific pattern, -exclude, or -max-matches)"
+	case errors.Is(err, http.ErrAmbiguousAsset):
+		return err.Error() + " (specify the asset's index or a glob pattern instead of 'auto')"
+	case errors.Is(err, http.ErrUnauthorized):
+		return err.Error() + " (check that the token is valid)"
+	default:
+		return err.Error()
+	}
+}
+
+// assetFilter This function returns the repository.AssetFilter for the given selector, which is '*' for all the assets,
+// a regular expression prefixed with "re:", or a glob pattern, and the filters' flags' values. The selectors different
+// from '*' must match with up to the given maximum amount of assets.
+func assetFilter(selector string, exclude string, contentType string, maxMatches int) repository.AssetFilter {
+	filter := repository.AssetFilter{ContentType: contentType}
+	if exclude != "" {
+		filter.Exclude = strings.Split(exclude, ",")
+	}
+	if selector == "*" {
+		return filter
+	}
+	filter.Limit = maxMatches
+	if regex, found := strings.CutPrefix(selector, "re:"); found {
+		filter.Regex = regex
+	} else {
+		filter.Glob = selector
+	}
+	return filter
+}
+
+// selectPlatformAsset This function returns the index of the release's asset built for the given platform, or for the
+// current one if it is empty, printing the reasons of the choice into the given writer.
+func selectPlatformAsset(out io.Writer, model *repository.GithubReleaseModel, platform string) (int, error) {
+	target := repository.CurrentPlatform()
+	if platform != "" {
+		parsed, err := repository.ParsePlatform(platform)
+		if err != nil {
+			return -1, err
+		}
+		target = parsed
+	}
+	index, err := model.SelectPlatform(target)
+	if err != nil {
+		return -1, err
+	}
+	// The best match is the first one, as they're sorted by score.
+	_, _ = fmt.Fprintf(out, "Selected asset %s for %s.\n", model.Explain(model.MatchPlatform(target)[0]), target)
+	return index, nil
+}
+
+// selectAsset This function returns the index of the only release's asset selected by the given selector, which is the
+// asset's index, 'auto' for the given platform's asset, or any selector accepted by assetFilter for the given filter.
+func selectAsset(out io.Writer, model *repository.GithubReleaseModel, selector string, filter repository.AssetFilter, platform string) (int, error) {
+	if index, err := strconv.Atoi(selector); err == nil {
+		return index - 1, nil
+	}
+	if selector == "auto" {
+		return selectPlatformAsset(out, model, platform)
+	}
+	return model.Select(filter)
 }
 
-func downloadAllAssets(directory string, model *repository.GithubReleaseModel) {
-	for index := range model.Assets {
-		downloadAsset(directory, model, index)
+// writeAsset This function writes the release's asset with the given index into the given output's file, or into the
+// standard output if it is '-', printing the progress and the result with the given printer. The output's file is written
+// as a part-file in the same directory, which is committed according to the configured conflict policy once the asset is
+// verified, or removed if it can't be written, so an existing file is never truncated.
+func writeAsset(ctx context.Context, output string, model *repository.GithubReleaseModel, index int, printer *progressPrinter) error {
+	if output == "-" {
+		result, err := model.DownloadToContext(ctx, os.Stdout, index, http.WithProgress(printer))
+		if err != nil {
+			printer.Finish("This asset couldn't be written:", describeError(err))
+			return err
+		}
+		printer.Finish(fmt.Sprintf("Wrote asset with name '%s' and '%d' read bytes (sha256:%s).", model.Assets[index].Name, result.Bytes, result.Sha256))
+		return nil
+	}
+	result, err := writeAssetFile(ctx, output, model, index, printer)
+	if err != nil {
+		_ = os.Remove(output + download.PartSuffix)
+		printer.Finish("This asset couldn't be written:", describeError(err))
+		return err
 	}
+	if result.Skipped() {
+		printer.Finish(fmt.Sprintf("Skipped asset with name '%s', as an identical file exists at '%s'.", model.Assets[index].Name, result.Path))
+		return nil
+	}
+	printer.Finish(fmt.Sprintf("Wrote asset with name '%s' and '%d' read bytes into '%s' (sha256:%s).", model.Assets[index].Name, result.Bytes, result.Path, result.Sha256))
+	return nil
+}
+
+// writeAssetFile This function writes the release's asset with the given index into the given output's part-file, and
+// commits it according to the configured conflict policy, returning the download's download.Result with the final path.
+// As the asset can't be compared before it's written, an existing output fails the http.ConflictFail policy before the
+// download, and the part-file is compared with it after the download for the http.ConflictSkipIdentical policy.
+func writeAssetFile(ctx context.Context, output string, model *repository.GithubReleaseModel, index int, printer *progressPrinter) (download.Result, error) {
+	policy := http.DefaultConfig.ConflictPolicy
+	if _, err := os.Lstat(output); err == nil && policy == http.ConflictFail {
+		return download.Result{}, fmt.Errorf("%w: '%s'", http.ErrFileExists, output)
+	}
+	file, err := os.Create(output + download.PartSuffix)
+	if err != nil {
+		return download.Result{}, fmt.Errorf("%w: %w", http.ErrFileSystem, err)
+	}
+	result, err := model.DownloadToContext(ctx, file, index, http.WithProgress(printer))
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("%w: %w", http.ErrFileSystem, closeErr)
+	}
+	if err != nil {
+		return result, err
+	}
+	if policy == http.ConflictSkipIdentical {
+		identical, err := identicalFile(output, result.Bytes, result.Sha256)
+		if err != nil {
+			return result, err
+		}
+		if identical {
+			_ = os.Remove(output + download.PartSuffix)
+			result.Status, result.Path = download.AssetSkippedStatus, output
+			return result, nil
+		}
+	}
+	result.Path, err = download.CommitPart(output, policy)
+	return result, err
+}
+
+// identicalFile This function returns whether the file in the given path exists, and has the given size and SHA-256
+// hex-digest.
+func identicalFile(path string, size int64, sha256Digest string) (bool, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", http.ErrFileSystem, err)
+	}
+	defer func(File *os.File) {
+		_ = File.Close()
+	}(file)
+	if stat, err := file.Stat(); err != nil || stat.Size() != size {
+		return false, nil
+	}
+	digest := sha256.New()
+	if _, err := io.Copy(digest, file); err != nil {
+		return false, fmt.Errorf("%w: %w", http.ErrFileSystem, err)
+	}
+	return hex.EncodeToString(digest.Sum(nil)) == sha256Digest, nil
+}
+
+// downloadAssets This function downloads the release's assets with the given indexes, up to the given amount at the same
+// time, continuing after the failed ones, and returns their reports in the same order.
+func downloadAssets(ctx context.Context, directory string, model *repository.GithubReleaseModel, indexes []int, extract *download.ExtractOptions, parallel int) []assetReport {
+	printer := newProgressPrinter(os.Stdout, len(indexes))
+	pool := async.NewPool(parallel)
+	futures := make([]*async.Future[assetReport], len(indexes))
+	for position, index := range indexes {
+		futures[position] = async.Submit(ctx, pool, func(ctx context.Context) (assetReport, error) {
+			return downloadAsset(ctx, directory, model, index, extract, printer), nil
+		})
+	}
+	reports := make([]assetReport, len(indexes))
+	for position, future := range futures {
+		report, err := future.Get()
+		if err != nil {
+			// The download wasn't started as the context is done, or it panicked.
+			report = failedReport(model.Assets[indexes[position]].Name, 0, err)
+		}
+		reports[position] = report
+	}
+	return reports
 }
 
 func printReleaseInformation(model *repository.GithubReleaseModel) {
@@ -132,6 +569,16 @@ func printReleaseInformation(model *repository.GithubReleaseModel) {
 	}
 }
 
+func printRateLimitInformation(model *repository.GithubRateLimitModel) {
+	fmt.Println("Showing rate-limit status for the API's resources:")
+	fmt.Println()
+	for _, name := range slices.Sorted(maps.Keys(model.Resources)) {
+		resource := model.Resources[name]
+		fmt.Printf("%s -> %d of %d remaining, resets at %s\n", name, resource.Remaining, resource.Limit,
+			resource.ResetTime().Format(time.DateTime))
+	}
+}
+
 func printRepositoryInformation(model *repository.GithubRepositoryModel) {
 	fmt.Println("Showing information for repository:", model.Name)
 	fmt.Println()
@@ -0,0 +1,78 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+	"viewer/main/http"
+	"viewer/main/repository"
+)
+
+func TestTagsPagination(t *testing.T) {
+	requests := 0
+	var server *httptest.Server
+	server = httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		requests++
+		if r.URL.Query().Get("per_page") != "2" {
+			t.Errorf("Unexpected per_page parameter '%s'.", r.URL.Query().Get("per_page"))
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		page = max(page, 1)
+		if page < 3 {
+			next := fmt.Sprintf("%s%s?per_page=2&page=%d", server.URL, r.URL.Path, page+1)
+			w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next", <%s>; rel="last"`, next, next))
+		}
+		_, _ = fmt.Fprintf(w, `[{"name":"v%d.0"},{"name":"v%d.1"}]`, page, page)
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+
+	request := repository.NewTagsRequest(http.DefaultConfig.TagsUrl("aivruu", "repo-viewer"))
+	var names []string
+	for tag, err := range http.RequestAll(context.Background(), request, 5*time.Second, http.PageOptions{PerPage: 2}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, tag.Name)
+	}
+	if fmt.Sprint(names) != "[v1.0 v1.1 v2.0 v2.1 v3.0 v3.1]" || requests != 3 {
+		t.Errorf("Unexpected tags %v after %d requests.", names, requests)
+	}
+
+	requests = 0
+	names = nil
+	for tag, err := range http.RequestAll(context.Background(), request, 5*time.Second, http.PageOptions{PerPage: 2, MaxItems: 3}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, tag.Name)
+	}
+	if fmt.Sprint(names) != "[v1.0 v1.1 v2.0]" || requests != 2 {
+		t.Errorf("Unexpected capped tags %v after %d requests.", names, requests)
+	}
+}
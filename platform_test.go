@@ -0,0 +1,94 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"testing"
+	"viewer/main/http"
+	"viewer/main/repository"
+)
+
+func TestPlatformAssetMatching(t *testing.T) {
+	model := &repository.GithubReleaseModel{Assets: []repository.Asset{
+		{Name: "app_1.0.0_amd64.deb"},
+		{Name: "app-1.0.0-x86_64-unknown-linux-gnu.tar.gz"},
+		{Name: "app-1.0.0-x86_64-unknown-linux-musl.tar.gz"},
+		{Name: "app-1.0.0-aarch64-unknown-linux-gnu.tar.gz"},
+		{Name: "app-1.0.0-aarch64-apple-darwin.tar.gz"},
+		{Name: "app-1.0.0-macos-universal.zip"},
+		{Name: "app-1.0.0-x86_64-pc-windows-msvc.zip"},
+		{Name: "app-1.0.0-x86_64-unknown-linux-gnu.tar.gz.sha256"},
+		{Name: "app-1.0.0.x86_64.rpm"},
+	}}
+	cases := map[string]int{
+		"linux/amd64":         1,
+		"linux/x86_64/musl":   2,
+		"linux/aarch64":       3,
+		"macos/arm64":         4,
+		"darwin/amd64":        5,
+		"windows/amd64":       6,
+		"linux/arm64/glibc":   3,
+		"darwin/x86-64":       5,
+		"linux/amd64/musl":    2,
+		"windows/x64":         6,
+		"linux/arm64/musl":    -1,
+		"freebsd/amd64":       -1,
+		"linux/riscv64/gnu":   -1,
+		"windows/arm64":       -1,
+		"linux/amd64/unknown": -2,
+	}
+	for text, expected := range cases {
+		platform, err := repository.ParsePlatform(text)
+		if expected == -2 {
+			if err == nil {
+				t.Errorf("Expected an error for the platform '%s'.", text)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		index, err := model.SelectPlatform(platform)
+		if expected == -1 {
+			if !errors.Is(err, http.ErrNoMatchingAsset) {
+				t.Errorf("Expected no asset for %s, got %d (%v)", platform, index, err)
+			}
+			continue
+		}
+		if index != expected || err != nil {
+			t.Errorf("Unexpected asset for %s: %d (%v)", platform, index, err)
+		}
+	}
+
+	ambiguous := &repository.GithubReleaseModel{Assets: []repository.Asset{
+		{Name: "app-linux-amd64"},
+		{Name: "app-linux-amd64.tar.gz"},
+		{Name: "app-linux-arm64.tar.gz"},
+	}}
+	if _, err := ambiguous.SelectPlatform(repository.Platform{OS: "linux", Arch: "amd64", Libc: "gnu"}); !errors.Is(err, http.ErrAmbiguousAsset) {
+		t.Errorf("Expected an ambiguous asset error, got %v", err)
+	}
+	if matches := ambiguous.MatchPlatform(repository.Platform{OS: "linux", Arch: "arm64"}); len(matches) != 1 || matches[0].Index != 2 {
+		t.Errorf("Unexpected matches for linux/arm64: %v", matches)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"viewer/main/http"
+)
+
+// progressBarWidth The amount of characters used by the progress bars.
+const progressBarWidth = 30
+
+// progressLogInterval The minimum time between two progress lines when the output is not a terminal.
+const progressLogInterval = 5 * time.Second
+
+// progressPrinter This struct renders the downloads' progress, and the aggregate progress of all of them. In terminals the
+// progress bars are redrawn in-place, otherwise periodic log lines are printed instead.
+type progressPrinter struct {
+	mutex     sync.Mutex
+	out       io.Writer
+	terminal  bool
+	assets    int
+	finished  int
+	active    []string
+	downloads map[string]http.Progress
+	lines     int
+	logged    time.Time
+}
+
+// newProgressPrinter This function creates a new progressPrinter for the given amount of assets, writing into the given file.
+func newProgressPrinter(file *os.File, assets int) *progressPrinter {
+	return &progressPrinter{
+		out:       file,
+		terminal:  isTerminal(file),
+		assets:    assets,
+		downloads: make(map[string]http.Progress),
+	}
+}
+
+// isTerminal This function returns whether the given file is a terminal (a character device).
+func isTerminal(file *os.File) bool {
+	stat, err := file.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// Progress This method updates the given download's progress, redrawing the progress bars if needed.
+func (p *progressPrinter) Progress(progress http.Progress) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if _, found := p.downloads[progress.Name]; !found {
+		p.active = append(p.active, progress.Name)
+	}
+	p.downloads[progress.Name] = progress
+	if progress.Finished {
+		for index, name := range p.active {
+			if name == progress.Name {
+				p.active = append(p.active[:index], p.active[index+1:]...)
+				break
+			}
+		}
+	}
+	if p.terminal {
+		p.clear()
+		if progress.Finished {
+			_, _ = fmt.Fprintln(p.out, progressLine(progress))
+		}
+		p.draw()
+		return
+	}
+	if progress.Finished || time.Since(p.logged) >= progressLogInterval {
+		p.logged = time.Now()
+		_, _ = fmt.Fprintln(p.out, progressLine(progress))
+		if p.assets > 1 {
+			_, _ = fmt.Fprintln(p.out, p.aggregateLine())
+		}
+	}
+}
+
+// Finish This method counts an asset as processed, successfully or not, printing the given values in a new line above the
+// progress bars.
+func (p *progressPrinter) Finish(values ...any) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.finished++
+	p.clear()
+	_, _ = fmt.Fprintln(p.out, values...)
+	p.draw()
+}
+
+// clear This method removes the drawn progress bars, moving the cursor to the first one's line.
+func (p *progressPrinter) clear() {
+	if !p.terminal || p.lines == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(p.out, "\033[%dA\033[J", p.lines)
+	p.lines = 0
+}
+
+// draw This method draws the progress bars of the active downloads, and the aggregate one for multiple assets.
+func (p *progressPrinter) draw() {
+	if !p.terminal {
+		return
+	}
+	for _, name := range p.active {
+		_, _ = fmt.Fprintln(p.out, progressLine(p.downloads[name]))
+		p.lines++
+	}
+	if p.assets > 1 && p.finished < p.assets {
+		_, _ = fmt.Fprintln(p.out, p.aggregateLine())
+		p.lines++
+	}
+}
+
+// aggregateLine This method returns the line that describes the progress of all the downloads.
+func (p *progressPrinter) aggregateLine() string {
+	var done, total int64
+	var elapsed time.Duration
+	for _, progress := range p.downloads {
+		done += progress.Done - progress.Offset
+		total += max(progress.Total-progress.Offset, 0)
+		elapsed = max(elapsed, progress.Elapsed)
+	}
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(done) / elapsed.Seconds()
+	}
+	return fmt.Sprintf("%-24s %s %d/%d assets  %s  %s/s", "Total", progressBar(float64(p.finished)/float64(p.assets)*100),
+		p.finished, p.assets, formatBytes(done), formatBytes(int64(rate)))
+}
+
+// progressLine This function returns the line that describes the given download's progress.
+func progressLine(progress http.Progress) string {
+	name := progress.Name
+	if len(name) > 24 {
+		name = name[:21] + "..."
+	}
+	rate := formatBytes(int64(progress.Rate())) + "/s"
+	if progress.Total < 0 {
+		return fmt.Sprintf("%-24s %s  %s", name, formatBytes(progress.Done), rate)
+	}
+	line := fmt.Sprintf("%-24s %s %3.0f%%  %s/%s  %s", name, progressBar(progress.Percent()), progress.Percent(),
+		formatBytes(progress.Done), formatBytes(progress.Total), rate)
+	if eta := progress.ETA(); !progress.Finished && eta >= 0 {
+		line += "  ETA " + eta.Round(time.Second).String()
+	}
+	return line
+}
+
+// progressBar This function returns a bar filled with the given percentage.
+func progressBar(percent float64) string {
+	filled := min(max(int(percent/100*progressBarWidth), 0), progressBarWidth)
+	return "[" + strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled) + "]"
+}
+
+// formatBytes This function returns the given amount of bytes using binary units.
+func formatBytes(amount int64) string {
+	const unit = 1024
+	if amount < unit {
+		return fmt.Sprintf("%d B", amount)
+	}
+	value, prefix := float64(amount), 0
+	for value >= unit && prefix < 4 {
+		value /= unit
+		prefix++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, "KMGT"[prefix-1])
+}
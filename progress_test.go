@@ -0,0 +1,73 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+	"viewer/main/download"
+	"viewer/main/http"
+)
+
+func TestDownloadProgress(t *testing.T) {
+	content := bytes.Repeat([]byte("progress"), 400_000)
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		nethttp.ServeContent(w, r, "asset.bin", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+
+	for _, segments := range []int{1, 4} {
+		var mutex sync.Mutex
+		updates := make([]http.Progress, 0)
+		observer := http.ProgressFunc(func(progress http.Progress) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			updates = append(updates, progress)
+		})
+		_, err := download.From(t.TempDir(), "asset.bin", server.URL+"/asset", http.WithProgress(observer), http.WithSegments(segments))
+		if err != nil {
+			t.Fatal(err)
+		}
+		last := updates[len(updates)-1]
+		if !last.Finished || last.Name != "asset.bin" || last.Done != int64(len(content)) || last.Total != int64(len(content)) {
+			t.Errorf("Unexpected last progress with %d segments: %+v", segments, last)
+		}
+		if last.Percent() != 100 || last.ETA() > 0 {
+			t.Errorf("Unexpected percent %f or ETA %s.", last.Percent(), last.ETA())
+		}
+	}
+}
+
+func TestProgressLine(t *testing.T) {
+	progress := http.Progress{Name: "asset.tar.gz", Done: 512 << 10, Total: 1 << 20, Elapsed: time.Second}
+	line := progressLine(progress)
+	expected := "asset.tar.gz             [===============               ]  50%  512.0 KiB/1.0 MiB  512.0 KiB/s  ETA 1s"
+	if line != expected {
+		t.Errorf("Unexpected progress line:\n'%s'\n'%s'", line, expected)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+	"viewer/main/http"
+	"viewer/main/utils"
+)
+
+func TestRateLimitPolicies(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		if requests < 3 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(nethttp.StatusForbidden)
+			return
+		}
+		w.Header().Set("X-RateLimit-Remaining", "59")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+
+	_, err := utils.Response(nethttp.DefaultClient, server.URL)
+	var limitErr *http.RateLimitError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("Expected a rate-limit error, got '%v'.", err)
+	}
+	if limitErr.RateLimit.Limit != 60 || limitErr.RateLimit.Remaining != 0 || limitErr.RateLimit.RetryAfter != time.Second {
+		t.Errorf("Unexpected rate-limit information: %+v", limitErr.RateLimit)
+	}
+
+	http.DefaultConfig.RateLimitPolicy = http.RateLimitWait
+	resp, err := utils.Response(nethttp.DefaultClient, server.URL)
+	if err != nil {
+		t.Fatalf("Expected the request to succeed after waiting, got '%v'.", err)
+	}
+	_ = resp.Close()
+	if resp.StatusCode != http.ResponseOkStatus || resp.RateLimit.Remaining != 59 {
+		t.Errorf("Unexpected response after waiting: status %d, remaining %d", resp.StatusCode, resp.RateLimit.Remaining)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package repository
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"viewer/main/http"
+)
+
+// AssetFilter This struct holds the criteria used to select a release's assets, an asset is selected when it matches
+// with all the specified criteria, and with none of the Exclude patterns.
+type AssetFilter struct {
+	Glob        string   // The path.Match pattern of the assets' names, such as "*linux*.tar.gz".
+	Regex       string   // The regular expression matching the assets' names.
+	Exclude     []string // The path.Match patterns of the assets' names that are never selected, such as "*.sha256".
+	ContentType string   // The assets' content-type, such as "application/gzip", or its prefix ending with '/'.
+	Limit       int      // The maximum amount of selected assets, zero if it's unlimited.
+}
+
+// String This method returns a description of the filter's criteria, used by the errors.
+func (f AssetFilter) String() string {
+	criteria := make([]string, 0, 4)
+	if f.Glob != "" {
+		criteria = append(criteria, fmt.Sprintf("glob '%s'", f.Glob))
+	}
+	if f.Regex != "" {
+		criteria = append(criteria, fmt.Sprintf("regex '%s'", f.Regex))
+	}
+	if f.ContentType != "" {
+		criteria = append(criteria, fmt.Sprintf("content-type '%s'", f.ContentType))
+	}
+	if len(f.Exclude) > 0 {
+		criteria = append(criteria, fmt.Sprintf("excluding '%s'", strings.Join(f.Exclude, "', '")))
+	}
+	if len(criteria) == 0 {
+		return "any asset"
+	}
+	return strings.Join(criteria, ", ")
+}
+
+// Filter This method returns the indexes of this release's assets selected by the given AssetFilter. It returns an error
+// matching with http.ErrNoMatchingAsset if no asset is selected, or http.ErrTooManyAssets if more assets than the filter's
+// Limit are selected, both describing the candidates, or an error if the filter's patterns are not valid.
+func (r *GithubReleaseModel) Filter(filter AssetFilter) ([]int, error) {
+	var regex *regexp.Regexp
+	if filter.Regex != "" {
+		compiled, err := regexp.Compile(filter.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid asset regex '%s': %w", filter.Regex, err)
+		}
+		regex = compiled
+	}
+	for _, pattern := range append([]string{filter.Glob}, filter.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid asset pattern '%s': %w", pattern, err)
+		}
+	}
+	indexes := make([]int, 0)
+	for index, asset := range r.Assets {
+		if matchesFilter(&asset, &filter, regex) {
+			indexes = append(indexes, index)
+		}
+	}
+	if len(indexes) == 0 {
+		return nil, fmt.Errorf("%w: %s among %s", http.ErrNoMatchingAsset, filter, r.assetsNames(nil))
+	}
+	if filter.Limit > 0 && len(indexes) > filter.Limit {
+		return nil, fmt.Errorf("%w: %s selects %d assets (%s), expected at most %d", http.ErrTooManyAssets, filter,
+			len(indexes), r.assetsNames(indexes), filter.Limit)
+	}
+	return indexes, nil
+}
+
+// Select This method returns the index of the only asset of this release selected by the given AssetFilter, failing as
+// Filter does when there isn't exactly one.
+func (r *GithubReleaseModel) Select(filter AssetFilter) (int, error) {
+	filter.Limit = 1
+	indexes, err := r.Filter(filter)
+	if err != nil {
+		return -1, err
+	}
+	return indexes[0], nil
+}
+
+// matchesFilter This function returns whether the given asset matches with the given AssetFilter, whose patterns are valid.
+func matchesFilter(asset *Asset, filter *AssetFilter, regex *regexp.Regexp) bool {
+	if filter.Glob != "" {
+		if matched, _ := path.Match(filter.Glob, asset.Name); !matched {
+			return false
+		}
+	}
+	if regex != nil && !regex.MatchString(asset.Name) {
+		return false
+	}
+	if filter.ContentType != "" {
+		contentType := strings.ToLower(asset.ContentType)
+		expected := strings.ToLower(filter.ContentType)
+		if contentType != expected && !(strings.HasSuffix(expected, "/") && strings.HasPrefix(contentType, expected)) {
+			return false
+		}
+	}
+	for _, pattern := range filter.Exclude {
+		if matched, _ := path.Match(pattern, asset.Name); matched {
+			return false
+		}
+	}
+	return true
+}
+
+// assetsNames This method returns the quoted names of the assets with the given indexes, or of all of them if the indexes
+// are nil.
+func (r *GithubReleaseModel) assetsNames(indexes []int) string {
+	names := make([]string, 0, len(r.Assets))
+	for index, asset := range r.Assets {
+		if indexes == nil || slices.Contains(indexes, index) {
+			names = append(names, "'"+asset.Name+"'")
+		}
+	}
+	if len(names) == 0 {
+		return "no assets"
+	}
+	return strings.Join(names, ", ")
+}
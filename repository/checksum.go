@@ -0,0 +1,183 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package repository
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"viewer/main/http"
+	"viewer/main/utils"
+)
+
+// maxChecksumAssetSize The maximum size of the checksum assets read to find an asset's checksum.
+const maxChecksumAssetSize = 1 << 20
+
+// assetChecksumSuffixes The suffixes of the checksum assets that provide a single asset's checksum.
+var assetChecksumSuffixes = []string{".sha256", ".sha256sum", ".sha256.txt"}
+
+// checksumListNames The names' suffixes of the checksum assets that provide the checksums of multiple assets, such as the
+// ones generated by sha256sum or GoReleaser ("<project>_<version>_checksums.txt").
+var checksumListNames = []string{"sha256sums", "sha256sums.txt", "checksums.txt", "checksums.sha256"}
+
+// Checksum This method returns the checksum of the asset-specified for this release, which looks like "sha256:<hex-digest>".
+// The checksum is taken from the asset's digest provided by the API, or from the release's checksum assets, such as
+// "<asset>.sha256", "SHA256SUMS" or "checksums.txt", returning an error matching with http.ErrNoChecksum if it can't be
+// found. The given options are used for the checksum assets' requests.
+func (r *GithubReleaseModel) Checksum(assetNum int, options ...http.RequestOption) (string, error) {
+	return r.ChecksumContext(context.Background(), assetNum, options...)
+}
+
+// ChecksumContext This method realizes the same execution that Checksum, the requests are aborted when the given context is
+// done.
+func (r *GithubReleaseModel) ChecksumContext(ctx context.Context, assetNum int, options ...http.RequestOption) (string, error) {
+	if assetNum < 0 || assetNum >= len(r.Assets) {
+		return "", fmt.Errorf("%w: index %d for %d assets", http.ErrInvalidAssetIndex, assetNum+1, len(r.Assets))
+	}
+	asset := r.Assets[assetNum]
+	if algorithm, digest, found := strings.Cut(asset.Digest, ":"); found && strings.EqualFold(algorithm, "sha256") {
+		return "sha256:" + strings.ToLower(digest), nil
+	}
+	errs := make([]error, 0)
+	for _, candidate := range r.checksumAssets(asset.Name) {
+		content, err := readAsset(ctx, &candidate, maxChecksumAssetSize, options...)
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			// Another checksum asset may provide the checksum.
+			errs = append(errs, fmt.Errorf("reading checksum asset '%s': %w", candidate.Name, err))
+			continue
+		}
+		if digest, found := findChecksum(content, asset.Name, specificChecksumAsset(candidate.Name, asset.Name)); found {
+			return "sha256:" + digest, nil
+		}
+	}
+	if len(errs) > 0 {
+		return "", fmt.Errorf("%w: no digest nor readable checksum asset for '%s': %w", http.ErrNoChecksum, asset.Name, errors.Join(errs...))
+	}
+	return "", fmt.Errorf("%w: no digest nor checksum asset for '%s'", http.ErrNoChecksum, asset.Name)
+}
+
+// checksumAssets This method returns this release's checksum assets that may provide the given asset's checksum, the ones
+// specific for the asset first.
+func (r *GithubReleaseModel) checksumAssets(name string) []Asset {
+	specific, lists := make([]Asset, 0), make([]Asset, 0)
+	for _, asset := range r.Assets {
+		if specificChecksumAsset(asset.Name, name) {
+			specific = append(specific, asset)
+		}
+		lowerName := strings.ToLower(asset.Name)
+		for _, listName := range checksumListNames {
+			if strings.HasSuffix(lowerName, listName) {
+				lists = append(lists, asset)
+				break
+			}
+		}
+	}
+	return append(specific, lists...)
+}
+
+// specificChecksumAsset This function returns whether the given checksum asset's name corresponds to the one that provides
+// just the given asset-name's checksum, such as "<asset>.sha256".
+func specificChecksumAsset(checksumName string, name string) bool {
+	lowerName := strings.ToLower(checksumName)
+	for _, suffix := range assetChecksumSuffixes {
+		if lowerName == strings.ToLower(name)+suffix {
+			return true
+		}
+	}
+	return false
+}
+
+// ChecksumAsset This method returns whether this asset provides checksums for other assets.
+func (a *Asset) ChecksumAsset() bool {
+	lowerName := strings.ToLower(a.Name)
+	for _, suffix := range slices.Concat(assetChecksumSuffixes, checksumListNames) {
+		if strings.HasSuffix(lowerName, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// readAsset This function returns the given small asset's content, such as a checksum or signature asset, which is read up
+// to the given size.
+func readAsset(ctx context.Context, asset *Asset, maxSize int64, options ...http.RequestOption) (string, error) {
+	url := asset.DownloadUrl()
+	if !http.DefaultConfig.ValidDownloadUrl(url) {
+		return "", fmt.Errorf("%w: '%s' doesn't belong to '%s'", http.ErrInvalidAssetUrl, url, http.DefaultConfig.BaseUrl())
+	}
+	resp, err := utils.OriginalResponseContext(ctx, url, options...)
+	if err != nil {
+		return "", err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxSize))
+	if err != nil {
+		return "", fmt.Errorf("%w: reading '%s': %w", http.ErrNetwork, url, err)
+	}
+	return string(content), nil
+}
+
+// findChecksum This function returns the given asset-name's SHA-256 hex-digest from the given checksum asset's content,
+// which may use the sha256sum format ("<digest>  <name>", with an optional '*' before the name), the BSD format
+// ("SHA256 (<name>) = <digest>"), or provide just the digest, which is only accepted when the given specific flag is true,
+// as the content belongs to the asset-specific checksum asset.
+func findChecksum(content string, name string, specific bool) (string, bool) {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		var digest, file string
+		if rest, found := strings.CutPrefix(line, "SHA256 ("); found {
+			file, digest, _ = strings.Cut(rest, ") = ")
+		} else {
+			fields := strings.Fields(line)
+			switch len(fields) {
+			case 1:
+				if !specific {
+					continue
+				}
+				digest, file = fields[0], name
+			case 2:
+				digest, file = fields[0], strings.TrimPrefix(fields[1], "*")
+			default:
+				continue
+			}
+		}
+		if path.Base(file) != name {
+			continue
+		}
+		if decoded, err := hex.DecodeString(digest); err == nil && len(decoded) == 32 {
+			return strings.ToLower(digest), true
+		}
+	}
+	return "", false
+}
@@ -22,13 +22,122 @@
 package codec
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
 	"viewer/main/common"
 )
 
+// ErrUnexpectedPayload This error is returned by the strict decoding when the payload's shape doesn't match the model's one.
+var ErrUnexpectedPayload = errors.New("unexpected payload")
+
 // Provider This interface is used to proportionate a scalable way to deserialize json-data into common.RequestableModel structs,
 // or implementations.
 type Provider[M common.RequestableModel] interface {
-	// From Uses the given information to return a deserialized model based on the given json-body, the model returned can
-	// be of any type that implements the common.RequestableModel interface.
-	From(json string) *M
+	// Decode Uses the given reader's json-stream to return a deserialized model, the model returned can be of any type that
+	// implements the common.RequestableModel interface, or an error if the stream couldn't be deserialized.
+	Decode(reader io.Reader) (*M, error)
+}
+
+// JSONProvider This struct is the default Provider implementation, which deserializes the json-stream using the model's
+// json-tags. With the Strict mode, the payloads with unknown fields, trailing data, or a different json-type than the model's
+// one (such as an array or null for a struct model) are rejected with ErrUnexpectedPayload.
+type JSONProvider[M common.RequestableModel] struct {
+	Strict bool
+}
+
+// NewJSONProvider This function creates a new JSONProvider for the model, using the strict mode if specified.
+func NewJSONProvider[M common.RequestableModel](strict bool) *JSONProvider[M] {
+	return &JSONProvider[M]{Strict: strict}
+}
+
+func (p *JSONProvider[M]) Decode(reader io.Reader) (*M, error) {
+	return DecodeJSON[M](reader, p.Strict)
+}
+
+// DecodeJSON This function deserializes the given reader's json-stream into a new model, as described by JSONProvider.
+func DecodeJSON[M common.RequestableModel](reader io.Reader, strict bool) (*M, error) {
+	if strict {
+		buffered := bufio.NewReader(reader)
+		if err := checkShape(buffered, reflect.TypeFor[M]()); err != nil {
+			return nil, err
+		}
+		reader = buffered
+	}
+	decoder := json.NewDecoder(reader)
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+	var model M
+	if err := decoder.Decode(&model); err != nil {
+		if strict && isUnknownField(err) {
+			return nil, fmt.Errorf("%w: %w", ErrUnexpectedPayload, err)
+		}
+		return nil, err
+	}
+	if strict {
+		if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: trailing data after the json-value", ErrUnexpectedPayload)
+		}
+	}
+	return &model, nil
+}
+
+// checkShape This function peeks the first json-token from the given reader, and returns an ErrUnexpectedPayload if it
+// doesn't correspond to the given model's type, objects for structs and maps, and arrays for slices and arrays.
+func checkShape(reader *bufio.Reader, modelType reflect.Type) error {
+	var expected byte
+	switch modelType.Kind() {
+	case reflect.Struct, reflect.Map:
+		expected = '{'
+	case reflect.Slice, reflect.Array:
+		expected = '['
+	default:
+		return nil
+	}
+	for {
+		peeked, err := reader.Peek(1)
+		if err != nil {
+			return fmt.Errorf("%w: empty payload", ErrUnexpectedPayload)
+		}
+		switch peeked[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = reader.ReadByte()
+			continue
+		case expected:
+			return nil
+		default:
+			return fmt.Errorf("%w: expected '%c' for %s but payload starts with '%c'", ErrUnexpectedPayload, expected,
+				modelType, peeked[0])
+		}
+	}
+}
+
+// isUnknownField This function returns whether the given decoding's error was caused by an unknown field, as the json package
+// doesn't provide a typed error for it.
+func isUnknownField(err error) bool {
+	return strings.HasPrefix(err.Error(), "json: unknown field")
+}
+
+// providers The registered Provider for each model's type.
+var providers sync.Map
+
+// Register This function registers the given Provider for the model's type, replacing any previous one, so the requests for
+// this model use it for the responses' deserialization.
+func Register[M common.RequestableModel](provider Provider[M]) {
+	providers.Store(reflect.TypeFor[M](), provider)
+}
+
+// Lookup This function returns the Provider registered for the model's type, or a non-strict JSONProvider if there is not
+// any registered one.
+func Lookup[M common.RequestableModel]() Provider[M] {
+	if provider, ok := providers.Load(reflect.TypeFor[M]()); ok {
+		return provider.(Provider[M])
+	}
+	return &JSONProvider[M]{}
 }
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"iter"
+	http2 "net/http"
+	"time"
+	"viewer/main/common"
+	"viewer/main/http"
+	"viewer/main/utils"
+)
+
+// RequestListModelImpl This http.ListRequestModel implementation is used to iterate over any paginated list endpoint, such as
+// the repositories' releases or tags.
+type RequestListModelImpl[M common.RequestableModel] struct {
+	url string
+}
+
+// NewListRequest This function creates a new RequestListModelImpl for the models provided by the given list endpoint's url.
+func NewListRequest[M common.RequestableModel](url string) *RequestListModelImpl[M] {
+	return &RequestListModelImpl[M]{url: url}
+}
+
+// NewReleasesRequest This function creates a new RequestListModelImpl for the repository's releases with the given url.
+func NewReleasesRequest(url string) *RequestListModelImpl[GithubReleaseModel] {
+	return NewListRequest[GithubReleaseModel](url)
+}
+
+// NewTagsRequest This function creates a new RequestListModelImpl for the repository's tags with the given url.
+func NewTagsRequest(url string) *RequestListModelImpl[GithubTagModel] {
+	return NewListRequest[GithubTagModel](url)
+}
+
+func (r *RequestListModelImpl[M]) All(ctx context.Context, client *http2.Client, timeout time.Duration, page http.PageOptions, options ...http.RequestOption) iter.Seq2[M, error] {
+	return utils.Paginate[M](ctx, utils.ValidateAndModifyTimeout(client, timeout), r.url, page, options...)
+}
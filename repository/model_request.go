@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	http2 "net/http"
+	"time"
+	"viewer/main/common"
+	"viewer/main/http"
+	"viewer/main/repository/codec"
+	"viewer/main/utils"
+)
+
+// RequestModelImpl This http.RequestModel implementation is used to handle http-requests for any model, which is deserialized
+// using the codec.Provider registered for its type, so new endpoints only need to define their model, and optionally
+// register their own codec.Provider.
+type RequestModelImpl[M common.RequestableModel] struct {
+	url string
+}
+
+var (
+	_ http.RequestModel[GithubReleaseModel]    = (*RequestReleaseModelImpl)(nil)
+	_ http.RequestModel[GithubRepositoryModel] = (*RequestRepositoryModelImpl)(nil)
+	_ http.RequestModel[GithubRateLimitModel]  = (*RequestRateLimitModelImpl)(nil)
+)
+
+// NewRequest This function creates a new RequestModelImpl for the model provided by the given url.
+func NewRequest[M common.RequestableModel](url string) *RequestModelImpl[M] {
+	return &RequestModelImpl[M]{url: url}
+}
+
+func (r *RequestModelImpl[M]) RequestWith(client *http2.Client, timeout time.Duration, options ...http.RequestOption) (*M, error) {
+	return r.RequestWithContext(context.Background(), client, timeout, options...)
+}
+
+func (r *RequestModelImpl[M]) RequestWithAndThen(client *http2.Client, consumer func(*M), timeout time.Duration, options ...http.RequestOption) (*M, error) {
+	return r.RequestWithAndThenContext(context.Background(), client, consumer, timeout, options...)
+}
+
+func (r *RequestModelImpl[M]) RequestWithContext(ctx context.Context, client *http2.Client, timeout time.Duration, options ...http.RequestOption) (*M, error) {
+	resp, err := utils.ResponseContext(ctx, utils.ValidateAndModifyTimeout(client, timeout), r.url, options...)
+	if err != nil {
+		return nil, err
+	}
+	// The model is deserialized while the response's body is read.
+	model, err := codec.Lookup[M]().Decode(resp.Body())
+	_ = resp.Close()
+	if err != nil {
+		return nil, &http.DecodeError{Url: r.url, Err: err}
+	}
+	return model, nil
+}
+
+// RequestWithAndThenContext The consumer is only executed when the model was requested and deserialized successfully.
+func (r *RequestModelImpl[M]) RequestWithAndThenContext(ctx context.Context, client *http2.Client, consumer func(*M), timeout time.Duration, options ...http.RequestOption) (*M, error) {
+	model, err := r.RequestWithContext(ctx, client, timeout, options...)
+	if err == nil {
+		consumer(model)
+	}
+	return model, err
+}
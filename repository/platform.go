@@ -0,0 +1,257 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package repository
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"unicode"
+	"viewer/main/http"
+	"viewer/main/signature"
+)
+
+var (
+	// osAliases The names used by the assets for each operating system, keyed by its GOOS value.
+	osAliases = map[string][]string{
+		"linux":   {"linux"},
+		"darwin":  {"darwin", "macos", "mac", "osx", "apple"},
+		"windows": {"windows", "win", "win32", "win64"},
+		"freebsd": {"freebsd"},
+		"openbsd": {"openbsd"},
+		"netbsd":  {"netbsd"},
+		"android": {"android"},
+	}
+	// archAliases The names used by the assets for each architecture, keyed by its GOARCH value. The "x86_64" and "x86-64"
+	// names are replaced by "amd64" before the assets' names are split.
+	archAliases = map[string][]string{
+		"amd64":   {"amd64", "x64", "64bit"},
+		"arm64":   {"arm64", "aarch64", "armv8"},
+		"386":     {"386", "i386", "i686", "x86", "32bit"},
+		"arm":     {"arm", "armv6", "armv7", "armv7l", "armhf", "armel"},
+		"riscv64": {"riscv64"},
+		"ppc64le": {"ppc64le"},
+		"s390x":   {"s390x"},
+	}
+	// libcAliases The names used by the assets for each C library, only meaningful for Linux.
+	libcAliases = map[string][]string{
+		"gnu":  {"gnu", "glibc"},
+		"musl": {"musl"},
+	}
+	// universalNames The names used by the macOS assets built for every architecture.
+	universalNames = []string{"universal", "universal2"}
+	// packageSuffixes The suffixes of the system packages and installers, which are never selected as they can't be used
+	// directly, and of the releases' metadata files.
+	packageSuffixes = []string{".deb", ".rpm", ".apk", ".msi", ".pkg", ".dmg", ".snap", ".flatpak", ".sbom", ".spdx.json", ".pem"}
+)
+
+// Platform This struct describes the operating system, architecture and C library targeted by a release's asset.
+type Platform struct {
+	OS   string // The GOOS value, such as "linux" or "darwin".
+	Arch string // The GOARCH value, such as "amd64" or "arm64".
+	Libc string // The C library, "gnu" or "musl", empty if it isn't relevant for the OS.
+}
+
+// CurrentPlatform This function returns the Platform of the running program, on Linux the C library is "musl" if its
+// dynamic loader is found, otherwise "gnu".
+func CurrentPlatform() Platform {
+	platform := Platform{OS: runtime.GOOS, Arch: runtime.GOARCH}
+	if platform.OS == "linux" {
+		platform.Libc = "gnu"
+		if loaders, _ := filepath.Glob("/lib/ld-musl-*"); len(loaders) > 0 {
+			platform.Libc = "musl"
+		}
+	}
+	return platform
+}
+
+// ParsePlatform This function parses a Platform written as "os/arch" or "os/arch/libc", such as "linux/arm64/musl", the
+// names can be any of the known aliases, like "macos/x86_64". If the C library isn't specified, "gnu" is used for Linux.
+func ParsePlatform(text string) (Platform, error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(text)), "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return Platform{}, fmt.Errorf("invalid platform '%s': expected 'os/arch' or 'os/arch/libc'", text)
+	}
+	if parts[1] == "x86_64" || parts[1] == "x86-64" {
+		parts[1] = "amd64"
+	}
+	platform := Platform{OS: canonicalName(osAliases, parts[0]), Arch: canonicalName(archAliases, parts[1])}
+	if len(parts) == 3 {
+		libc, known := libcAliases[canonicalName(libcAliases, parts[2])]
+		if !known {
+			return Platform{}, fmt.Errorf("invalid platform '%s': unknown C library '%s'", text, parts[2])
+		}
+		platform.Libc = libc[0]
+	} else if platform.OS == "linux" {
+		platform.Libc = "gnu"
+	}
+	return platform, nil
+}
+
+func (p Platform) String() string {
+	if p.Libc == "" {
+		return p.OS + "/" + p.Arch
+	}
+	return p.OS + "/" + p.Arch + "/" + p.Libc
+}
+
+// PlatformMatch This struct holds a release's asset matching with a Platform, and the reasons of its score.
+type PlatformMatch struct {
+	Index   int      // The asset's index in the release's assets.
+	Score   int      // The match's score, higher scores are better matches.
+	Reasons []string // The descriptions of the matched names, such as "linux" or "any architecture".
+}
+
+// MatchPlatform This method returns the release's assets which can be used on the given Platform, sorted by score from the
+// best match. The assets built for another OS, architecture or incompatible C library are excluded, as well as the system
+// packages (such as .deb or .rpm), the checksums and the signatures.
+func (r *GithubReleaseModel) MatchPlatform(platform Platform) []PlatformMatch {
+	matches := make([]PlatformMatch, 0)
+	for index := range r.Assets {
+		if match, ok := matchPlatform(&r.Assets[index], &platform); ok {
+			match.Index = index
+			matches = append(matches, match)
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b PlatformMatch) int {
+		return b.Score - a.Score
+	})
+	return matches
+}
+
+// SelectPlatform This method returns the index of the best release's asset for the given Platform. It returns an error
+// matching with http.ErrNoMatchingAsset if no asset matches, or http.ErrAmbiguousAsset if the best matches have the same
+// score, which explains why each of them was considered.
+func (r *GithubReleaseModel) SelectPlatform(platform Platform) (int, error) {
+	matches := r.MatchPlatform(platform)
+	if len(matches) == 0 {
+		return -1, fmt.Errorf("%w: no asset for %s among %s", http.ErrNoMatchingAsset, platform, r.assetsNames(nil))
+	}
+	best := 1
+	for best < len(matches) && matches[best].Score == matches[0].Score {
+		best++
+	}
+	if best > 1 {
+		candidates := make([]string, 0, best)
+		for _, match := range matches[:best] {
+			candidates = append(candidates, r.Explain(match))
+		}
+		return -1, fmt.Errorf("%w: %d assets match %s equally: %s", http.ErrAmbiguousAsset, best, platform,
+			strings.Join(candidates, ", "))
+	}
+	return matches[0].Index, nil
+}
+
+// Explain This method returns the description of the given match, with its asset's name and the match's reasons.
+func (r *GithubReleaseModel) Explain(match PlatformMatch) string {
+	return fmt.Sprintf("'%s' (%s)", r.Assets[match.Index].Name, strings.Join(match.Reasons, ", "))
+}
+
+// matchPlatform This function scores the given asset's name against the given Platform, returning false if the asset can't
+// be used on it. The asset must name the Platform's OS, an asset naming no architecture or C library is a worse match
+// than the ones naming the Platform's ones.
+func matchPlatform(asset *Asset, platform *Platform) (PlatformMatch, bool) {
+	lowerName := strings.ToLower(asset.Name)
+	if asset.ChecksumAsset() || signature.IsSignature(lowerName) || hasAnySuffix(lowerName, packageSuffixes) {
+		return PlatformMatch{}, false
+	}
+	tokens := nameTokens(lowerName)
+	systems := aliasesIn(osAliases, tokens)
+	if strings.HasSuffix(lowerName, ".exe") && !slices.Contains(systems, "windows") {
+		systems = append(systems, "windows")
+	}
+	if !slices.Contains(systems, platform.OS) {
+		return PlatformMatch{}, false
+	}
+	match := PlatformMatch{Score: 8, Reasons: []string{platform.OS}}
+	architectures := aliasesIn(archAliases, tokens)
+	switch {
+	case slices.Contains(architectures, platform.Arch):
+		match.Score += 4
+		match.Reasons = append(match.Reasons, platform.Arch)
+	case platform.OS == "darwin" && slices.ContainsFunc(tokens, func(token string) bool {
+		return slices.Contains(universalNames, token)
+	}):
+		match.Score += 2
+		match.Reasons = append(match.Reasons, "universal")
+	case len(architectures) == 0:
+		match.Score += 1
+		match.Reasons = append(match.Reasons, "any architecture")
+	default:
+		return PlatformMatch{}, false
+	}
+	if platform.Libc == "" {
+		return match, true
+	}
+	libraries := aliasesIn(libcAliases, tokens)
+	switch {
+	case slices.Contains(libraries, platform.Libc):
+		match.Score += 2
+		match.Reasons = append(match.Reasons, platform.Libc)
+	case len(libraries) == 0:
+		match.Score += 1
+	case platform.Libc == "gnu" && slices.Contains(libraries, "musl"):
+		// The musl builds are usually static, so they work on glibc systems too.
+		match.Reasons = append(match.Reasons, "musl")
+	default:
+		return PlatformMatch{}, false
+	}
+	return match, true
+}
+
+// nameTokens This function splits the given lower-case asset's name into its alphanumeric words, the "x86_64" and "x86-64"
+// architectures and the "64-bit" and "32-bit" words are normalized before, so they're kept as a single word.
+func nameTokens(lowerName string) []string {
+	lowerName = strings.NewReplacer("x86_64", "amd64", "x86-64", "amd64", "64-bit", "64bit", "32-bit", "32bit").Replace(lowerName)
+	return strings.FieldsFunc(lowerName, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// aliasesIn This function returns the keys of the given aliases whose names are among the given tokens.
+func aliasesIn(aliases map[string][]string, tokens []string) []string {
+	found := make([]string, 0, 1)
+	for key, names := range aliases {
+		if slices.ContainsFunc(names, func(name string) bool { return slices.Contains(tokens, name) }) {
+			found = append(found, key)
+		}
+	}
+	return found
+}
+
+// canonicalName This function returns the key of the given aliases including the given name, or the name itself if none
+// of them includes it.
+func canonicalName(aliases map[string][]string, name string) string {
+	for key, names := range aliases {
+		if slices.Contains(names, name) {
+			return key
+		}
+	}
+	return name
+}
+
+// hasAnySuffix This function returns whether the given name ends with any of the given suffixes.
+func hasAnySuffix(name string, suffixes []string) bool {
+	return slices.ContainsFunc(suffixes, func(suffix string) bool { return strings.HasSuffix(name, suffix) })
+}
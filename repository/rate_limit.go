@@ -0,0 +1,48 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package repository
+
+import (
+	"time"
+	"viewer/main/common"
+)
+
+type (
+	// GithubRateLimitModel This struct represents the rate-limit status of the client for each one of the API's resources.
+	GithubRateLimitModel struct {
+		Resources map[string]RateLimitResource `json:"resources"`
+		common.RequestableModel
+	}
+
+	// RateLimitResource Provides the rate-limit status for an API's resource, such as "core" or "search".
+	RateLimitResource struct {
+		Limit     int   `json:"limit"`
+		Remaining int   `json:"remaining"`
+		Used      int   `json:"used"`
+		Reset     int64 `json:"reset"`
+	}
+)
+
+// ResetTime This method returns the time when this resource's rate limit is reset.
+func (r *RateLimitResource) ResetTime() time.Time {
+	return time.Unix(r.Reset, 0)
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"io"
+	"viewer/main/repository/codec"
+)
+
+func init() {
+	codec.Register[GithubRateLimitModel](&RateLimitCodecProvider{})
+}
+
+// RateLimitCodecProvider This struct is an implementation used for repository.GithubRateLimitModel deserialization,
+// registered as the default codec.Provider for this model.
+type RateLimitCodecProvider struct {
+	Strict bool // Whether the payloads with unexpected shapes are rejected, as described by codec.JSONProvider.
+}
+
+var _ codec.Provider[GithubRateLimitModel] = (*RateLimitCodecProvider)(nil)
+
+// Decode This function's implementation is used to handle and deserialize correctly the json-stream's information to create
+// a new repository.GithubRateLimitModel object.
+func (c *RateLimitCodecProvider) Decode(reader io.Reader) (*GithubRateLimitModel, error) {
+	return codec.DecodeJSON[GithubRateLimitModel](reader, c.Strict)
+}
@@ -0,0 +1,10 @@
+package repository
+
+// RequestRateLimitModelImpl This http.RequestModel implementation is used to handle requests for the client's rate-limit
+// status.
+type RequestRateLimitModelImpl = RequestModelImpl[GithubRateLimitModel]
+
+// NewRateLimitRequest This function creates a new RequestRateLimitModelImpl with the given url.
+func NewRateLimitRequest(url string) *RequestRateLimitModelImpl {
+	return NewRequest[GithubRateLimitModel](url)
+}
@@ -22,11 +22,19 @@
 package repository
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+	"viewer/main/async"
 	"viewer/main/common"
 	"viewer/main/download"
+	"viewer/main/http"
 	"viewer/main/repository/operator"
+	"viewer/main/signature"
 )
 
 // GithubReleaseModel This struct stores all necessary information for the repository's requested release.
@@ -36,6 +44,7 @@ type (
 		TagName  string  `json:"tag_name"`
 		Name     string  `json:"name"`
 		UniqueId int     `json:"id"`
+		ApiUrl   string  `json:"url"`
 		Assets   []Asset `json:"assets"`
 		common.RequestableModel
 	}
@@ -45,26 +54,155 @@ type (
 		Login string `json:"login"`
 	}
 
-	// Asset This struct stores a release's asset's name and url to be used for downloading later.
+	// Asset This struct stores a release's asset's name and urls to be used for downloading later, its content-type and size,
+	// and its digest (such as "sha256:<hex-digest>") when it's provided by the API.
 	Asset struct {
-		Name string `json:"name"`
-		Url  string `json:"browser_download_url"`
+		Name        string `json:"name"`
+		Url         string `json:"browser_download_url"`
+		ApiUrl      string `json:"url"`
+		Digest      string `json:"digest"`
+		ContentType string `json:"content_type"`
+		Size        int64  `json:"size"`
 	}
 )
 
+// DownloadUrl This method returns the url used to download this asset, which is the API's url when a token is configured
+// (required for private repositories' assets), otherwise the browser's download url.
+func (a *Asset) DownloadUrl() string {
+	if http.DefaultConfig.Token != "" && a.ApiUrl != "" {
+		return a.ApiUrl
+	}
+	return a.Url
+}
+
 // Download This method tries to download the asset-specified for this release from the array of assets into specified directory,
-// and will return a boolean value whether the asset-number is valid, and asset was downloaded correctly.
-func (r *GithubReleaseModel) Download(directory string, assetNum int) int64 {
-	if assetNum < 0 {
-		return download.UnknownAssetDefaultSize
+// and will return the download's download.Result, or an error if the asset-number is not valid (http.ErrInvalidAssetIndex),
+// or the asset couldn't be downloaded, which is also the Result's Err. The given options are used for the download's
+// request. If the verification is enabled with the http.WithVerify option (or the http.DefaultConfig's Verify), the asset's
+// Checksum is used to verify the download, except for the checksum assets themselves, failing if it can't be found. If
+// there are trusted keys for the release's repository, or they're specified with the http.WithTrustedKeys option, the
+// downloaded asset's signature is verified as VerifySignature does (except for the signatures themselves) before the file
+// is committed, so it's discarded if it can't be verified. The Result's Duration includes the verifications.
+func (r *GithubReleaseModel) Download(directory string, assetNum int, options ...http.RequestOption) (download.Result, error) {
+	return r.DownloadContext(context.Background(), directory, assetNum, options...)
+}
+
+// DownloadContext This method realizes the same execution that Download, the download is aborted when the given context is
+// done.
+func (r *GithubReleaseModel) DownloadContext(ctx context.Context, directory string, assetNum int, options ...http.RequestOption) (download.Result, error) {
+	start := time.Now()
+	options, keys, err := r.downloadOptions(ctx, assetNum, options...)
+	if err != nil {
+		return finishResult(download.Result{}, start, err)
 	}
-	assetsAmount := len(r.Assets)
-	if assetsAmount == 0 || assetNum >= assetsAmount {
-		return download.InvalidAssetDefaultSize
+	asset := r.Assets[assetNum]
+	if len(keys) > 0 && !signature.IsSignature(asset.Name) {
+		verify, err := r.signatureVerifier(ctx, assetNum, keys, options...)
+		if err != nil {
+			return finishResult(download.Result{}, start, err)
+		}
+		// The part-file is verified before it's committed, so unverified content never replaces an existing file.
+		options = append(slices.Clone(options), http.WithValidator(func(path string) error {
+			return verifyFile(verify, path)
+		}))
+	}
+	result, err := download.FromContext(ctx, directory, asset.Name, asset.DownloadUrl(), options...)
+	return finishResult(result, start, err)
+}
+
+// DownloadAndExtract This method realizes the same execution that Download, and then extracts the downloaded archive into
+// the same directory using the given download.ExtractOptions, returning the extracted files' paths. The archive is only
+// extracted once it's verified, and it is kept after the extraction.
+func (r *GithubReleaseModel) DownloadAndExtract(directory string, assetNum int, extract download.ExtractOptions, options ...http.RequestOption) ([]string, error) {
+	return r.DownloadAndExtractContext(context.Background(), directory, assetNum, extract, options...)
+}
+
+// DownloadAndExtractContext This method realizes the same execution that DownloadAndExtract, the download is aborted when
+// the given context is done.
+func (r *GithubReleaseModel) DownloadAndExtractContext(ctx context.Context, directory string, assetNum int, extract download.ExtractOptions, options ...http.RequestOption) ([]string, error) {
+	if assetNum >= 0 && assetNum < len(r.Assets) {
+		// Fail before the download if the asset can't be extracted.
+		if _, found := download.FormatOf(r.Assets[assetNum].Name); !found {
+			return nil, fmt.Errorf("%w: '%s'", http.ErrUnsupportedArchive, r.Assets[assetNum].Name)
+		}
+	}
+	result, err := r.DownloadContext(ctx, directory, assetNum, options...)
+	if err != nil {
+		return nil, err
+	}
+	return download.Extract(result.Path, directory, extract)
+}
+
+// DownloadTo This method downloads the asset-specified for this release into the given writer, such as the standard output
+// of a pipeline, and returns the download's download.Result, verifying it as Download does. As the content is written
+// while it's downloaded, the verifications' errors are returned once it was written, so the writer's consumer must
+// discard it.
+func (r *GithubReleaseModel) DownloadTo(writer io.Writer, assetNum int, options ...http.RequestOption) (download.Result, error) {
+	return r.DownloadToContext(context.Background(), writer, assetNum, options...)
+}
+
+// DownloadToContext This method realizes the same execution that DownloadTo, the download is aborted when the given
+// context is done.
+func (r *GithubReleaseModel) DownloadToContext(ctx context.Context, writer io.Writer, assetNum int, options ...http.RequestOption) (download.Result, error) {
+	start := time.Now()
+	options, keys, err := r.downloadOptions(ctx, assetNum, options...)
+	if err != nil {
+		return finishResult(download.Result{}, start, err)
 	}
 	asset := r.Assets[assetNum]
-	downloadStatus := download.From(directory, asset.Name, asset.Url)
-	return downloadStatus.Result
+	if len(keys) == 0 || signature.IsSignature(asset.Name) {
+		result, err := download.ToWriterContext(ctx, writer, asset.Name, asset.DownloadUrl(), options...)
+		return finishResult(result, start, err)
+	}
+	verify, err := r.signatureVerifier(ctx, assetNum, keys, options...)
+	if err != nil {
+		return finishResult(download.Result{}, start, err)
+	}
+	// The content is verified while it's written, as it can't be read again.
+	reader, pipe := io.Pipe()
+	verification := async.NewFuture(func() (struct{}, error) {
+		err := verify(reader)
+		// Drain the content not read by a failed verification, so the download isn't blocked.
+		_, _ = io.Copy(io.Discard, reader)
+		return struct{}{}, err
+	})
+	result, err := download.ToWriterContext(ctx, io.MultiWriter(writer, pipe), asset.Name, asset.DownloadUrl(), options...)
+	_ = pipe.CloseWithError(err)
+	if _, verifyErr := verification.Get(); err == nil {
+		err = verifyErr
+	}
+	return finishResult(result, start, err)
+}
+
+// finishResult This function returns the given download.Result for a download started at the given time, including its
+// verifications, which failed with the given error, if any.
+func finishResult(result download.Result, start time.Time, err error) (download.Result, error) {
+	result.Duration = time.Since(start)
+	if err != nil {
+		return result.WithError(err), err
+	}
+	return result, nil
+}
+
+// downloadOptions This method validates the asset-specified for this release's index, and returns the given options
+// including the asset's checksum when the verification is enabled, and the keys trusted for the asset's signature.
+func (r *GithubReleaseModel) downloadOptions(ctx context.Context, assetNum int, options ...http.RequestOption) ([]http.RequestOption, []*signature.PublicKey, error) {
+	assetsAmount := len(r.Assets)
+	if assetNum < 0 || assetNum >= assetsAmount {
+		return nil, nil, fmt.Errorf("%w: index %d for %d assets", http.ErrInvalidAssetIndex, assetNum+1, assetsAmount)
+	}
+	if requestOptions := http.DefaultConfig.Options(options...); requestOptions.Verify && requestOptions.Checksum == "" && !r.Assets[assetNum].ChecksumAsset() {
+		checksum, err := r.ChecksumContext(ctx, assetNum, options...)
+		if err != nil {
+			return nil, nil, err
+		}
+		options = append(slices.Clone(options), http.WithChecksum(checksum))
+	}
+	keys, err := r.trustedKeys(options...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return options, keys, nil
 }
 
 // Compare This method compares the given version-number with this release's tag-name (as int) using the specified operator-type
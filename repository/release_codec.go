@@ -1,21 +1,24 @@
 package repository
 
 import (
-	json2 "encoding/json"
+	"io"
 	"viewer/main/repository/codec"
 )
 
-// ReleaseCodecProvider This struct is an implementation used for repository.GithubReleaseModel deserialization.
+func init() {
+	codec.Register[GithubReleaseModel](&ReleaseCodecProvider{})
+}
+
+// ReleaseCodecProvider This struct is an implementation used for repository.GithubReleaseModel deserialization,
+// registered as the default codec.Provider for this model.
 type ReleaseCodecProvider struct {
-	codec.Provider[GithubReleaseModel]
+	Strict bool // Whether the payloads with unexpected shapes are rejected, as described by codec.JSONProvider.
 }
 
-// From This function's override is used to handle and deserialize correctly the json's information to create a new
-// repository.GithubReleaseModel object.
-func (c *ReleaseCodecProvider) From(json string) (*GithubReleaseModel, error) {
-	var model GithubReleaseModel
-	if err := json2.Unmarshal([]byte(json), &model); err != nil {
-		return nil, err
-	}
-	return &model, nil
+var _ codec.Provider[GithubReleaseModel] = (*ReleaseCodecProvider)(nil)
+
+// Decode This function's implementation is used to handle and deserialize correctly the json-stream's information to create
+// a new repository.GithubReleaseModel object.
+func (c *ReleaseCodecProvider) Decode(reader io.Reader) (*GithubReleaseModel, error) {
+	return codec.DecodeJSON[GithubReleaseModel](reader, c.Strict)
 }
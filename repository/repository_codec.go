@@ -1,21 +1,24 @@
 package repository
 
 import (
-	json2 "encoding/json"
+	"io"
 	"viewer/main/repository/codec"
 )
 
-// RepositoryCodecProvider This struct is an implementation used for repository.GithubRepositoryModel deserialization.
+func init() {
+	codec.Register[GithubRepositoryModel](&RepositoryCodecProvider{})
+}
+
+// RepositoryCodecProvider This struct is an implementation used for repository.GithubRepositoryModel deserialization,
+// registered as the default codec.Provider for this model.
 type RepositoryCodecProvider struct {
-	codec.Provider[GithubRepositoryModel]
+	Strict bool // Whether the payloads with unexpected shapes are rejected, as described by codec.JSONProvider.
 }
 
-// From This function's override is used to handle and deserialize correctly the json's information to create a new
-// repository.GithubRepositoryModel object.
-func (r *RepositoryCodecProvider) From(json string) (*GithubRepositoryModel, error) {
-	var model GithubRepositoryModel
-	if err := json2.Unmarshal([]byte(json), &model); err != nil {
-		return nil, err
-	}
-	return &model, nil
+var _ codec.Provider[GithubRepositoryModel] = (*RepositoryCodecProvider)(nil)
+
+// Decode This function's implementation is used to handle and deserialize correctly the json-stream's information to create
+// a new repository.GithubRepositoryModel object.
+func (c *RepositoryCodecProvider) Decode(reader io.Reader) (*GithubRepositoryModel, error) {
+	return codec.DecodeJSON[GithubRepositoryModel](reader, c.Strict)
 }
@@ -0,0 +1,180 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"viewer/main/async"
+	"viewer/main/http"
+	"viewer/main/signature"
+)
+
+// maxSignatureAssetSize The maximum size of the signature assets read to verify an asset's signature.
+const maxSignatureAssetSize = 64 << 10
+
+// Repository This method returns the release's repository as "owner/name", taken from the release's API url, or an empty
+// string if it's not known.
+func (r *GithubReleaseModel) Repository() string {
+	parsed, err := url.Parse(r.ApiUrl)
+	if err != nil {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	for index, segment := range segments {
+		if segment == "repos" && index+2 < len(segments) {
+			return segments[index+1] + "/" + segments[index+2]
+		}
+	}
+	return ""
+}
+
+// trustedKeys This method returns the public keys trusted for the release's assets, which are the ones specified with the
+// http.WithTrustedKeys option, or the ones trusted for the release's repository by the http.DefaultConfig. If there are
+// trusted keys but the release's repository is not known, it fails with http.ErrNoSignature, as its keys can't be chosen.
+func (r *GithubReleaseModel) trustedKeys(options ...http.RequestOption) ([]*signature.PublicKey, error) {
+	keys := http.DefaultConfig.Options(options...).TrustedKeys
+	if len(keys) == 0 && len(http.DefaultConfig.TrustedKeys) > 0 {
+		repository := r.Repository()
+		if repository == "" {
+			return nil, fmt.Errorf("%w: the release's repository is not known to choose its trusted keys", http.ErrNoSignature)
+		}
+		keys = http.DefaultConfig.RepositoryKeys(repository)
+	}
+	publicKeys := make([]*signature.PublicKey, 0, len(keys))
+	for _, key := range keys {
+		publicKey, err := signature.ParsePublicKey(key)
+		if err != nil {
+			return nil, err
+		}
+		publicKeys = append(publicKeys, publicKey)
+	}
+	return publicKeys, nil
+}
+
+// VerifySignature This method verifies the given file, which is the downloaded asset-specified for this release, against
+// its detached signature assets (such as "<asset>.minisig") using the given trusted keys, succeeding if any of them matches.
+// It returns an error matching with http.ErrNoSignature if the release doesn't provide the asset's signature,
+// http.ErrUnsupportedFormat if none of them can be verified, as it happens with the OpenPGP ones, or
+// http.ErrInvalidSignature if they don't match.
+func (r *GithubReleaseModel) VerifySignature(ctx context.Context, assetNum int, path string, keys []*signature.PublicKey, options ...http.RequestOption) error {
+	verify, err := r.signatureVerifier(ctx, assetNum, keys, options...)
+	if err != nil {
+		return err
+	}
+	return verifyFile(verify, path)
+}
+
+// verifyFile This function verifies the given file's content with the given verification function.
+func verifyFile(verify func(io.Reader) error, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%w: %w", http.ErrFileSystem, err)
+	}
+	defer func(File *os.File) {
+		_ = File.Close()
+	}(file)
+	return verify(file)
+}
+
+// signatureVerifier This method reads the detached signature assets of the asset-specified for this release, and returns a
+// function that verifies the asset's content against them using the given trusted keys, failing as VerifySignature does.
+// The signatures using unsupported formats, or whose content is not recognized, are skipped, unless the release doesn't
+// provide any other.
+func (r *GithubReleaseModel) signatureVerifier(ctx context.Context, assetNum int, keys []*signature.PublicKey, options ...http.RequestOption) (func(io.Reader) error, error) {
+	asset := r.Assets[assetNum]
+	names := signature.Names(asset.Name)
+	verifiers := make([]func(io.Reader) error, 0, len(names))
+	unsupported := make([]string, 0, len(names))
+	for _, candidate := range r.Assets {
+		format, found := names[candidate.Name]
+		if !found {
+			continue
+		}
+		if !signature.Supported(format) {
+			unsupported = append(unsupported, candidate.Name)
+			continue
+		}
+		content, err := readAsset(ctx, &candidate, maxSignatureAssetSize, options...)
+		if err != nil {
+			return nil, fmt.Errorf("reading signature asset '%s': %w", candidate.Name, err)
+		}
+		if !signature.Recognized(format, []byte(content)) {
+			unsupported = append(unsupported, candidate.Name)
+			continue
+		}
+		verifiers = append(verifiers, func(reader io.Reader) error {
+			if err := signature.Verify(format, reader, []byte(content), keys); err != nil {
+				return fmt.Errorf("verifying '%s' with '%s': %w", asset.Name, candidate.Name, err)
+			}
+			return nil
+		})
+	}
+	switch {
+	case len(verifiers) == 1:
+		return verifiers[0], nil
+	case len(verifiers) > 1:
+		return anyVerifier(verifiers), nil
+	case len(unsupported) > 0:
+		return nil, fmt.Errorf("%w: '%s' can't be verified", http.ErrUnsupportedFormat, strings.Join(unsupported, "', '"))
+	default:
+		return nil, fmt.Errorf("%w: no signature asset for '%s'", http.ErrNoSignature, asset.Name)
+	}
+}
+
+// anyVerifier This function returns a function that verifies the content with all the given verification functions at
+// the same time, as it can be read only once, succeeding if any of them succeeds, or joining their errors otherwise.
+func anyVerifier(verifiers []func(io.Reader) error) func(io.Reader) error {
+	return func(reader io.Reader) error {
+		pipes := make([]*io.PipeWriter, len(verifiers))
+		writers := make([]io.Writer, len(verifiers))
+		futures := make([]*async.Future[struct{}], len(verifiers))
+		for index, verify := range verifiers {
+			pipeReader, pipe := io.Pipe()
+			pipes[index], writers[index] = pipe, pipe
+			futures[index] = async.NewFuture(func() (struct{}, error) {
+				err := verify(pipeReader)
+				// Drain the content not read by a failed verification, so the other ones aren't blocked.
+				_, _ = io.Copy(io.Discard, pipeReader)
+				return struct{}{}, err
+			})
+		}
+		_, err := io.Copy(io.MultiWriter(writers...), reader)
+		for _, pipe := range pipes {
+			_ = pipe.CloseWithError(err)
+		}
+		errs := make([]error, len(futures))
+		for index, future := range futures {
+			_, errs[index] = future.Get()
+		}
+		if slices.Contains(errs, nil) {
+			return nil
+		}
+		return errors.Join(errs...)
+	}
+}
@@ -32,9 +32,9 @@ import (
 func TestReleaseRequest(t *testing.T) {
 	// Request test to repository's latest release.
 	releaseRequest := repository.NewReleaseRequest(ForRelease("aivruu", "repo-viewer", "v3.4.7"))
-	release := http.Request(releaseRequest, 5*time.Second)
-	if release == nil {
-		t.Error("Failed to request the release for this repository.")
+	release, err := http.Request(releaseRequest, 5*time.Second)
+	if err != nil {
+		t.Error("Failed to request the release for this repository.", err)
 	} else {
 		t.Logf("%s - %s - %s", release.Author.Login, release.Name, release.TagName)
 		t.Log()
@@ -50,7 +50,7 @@ func TestReleaseRequest(t *testing.T) {
 func TestRepositoryRequest(t *testing.T) {
 	// Test using "consumer" function to print repository's information if it is available.
 	repositoryRequest := repository.NewRepositoryRequest(ForRepository("aivruu", "repo-viewer"))
-	model := http.RequestAndThen(repositoryRequest, func(Model *repository.GithubRepositoryModel) {
+	model, err := http.RequestAndThen(repositoryRequest, func(Model *repository.GithubRepositoryModel) {
 		t.Logf("%s - %s - %s", Model.Owner, Model.Name, Model.LicenseType.Name)
 		t.Log()
 		t.Log(Model.Archived)
@@ -60,6 +60,6 @@ func TestRepositoryRequest(t *testing.T) {
 		t.Log(Model.Language)
 	}, 5*time.Second)
 	if model == nil {
-		t.Log("Failed to request the repository.")
+		t.Log("Failed to request the repository.", err)
 	}
 }
@@ -0,0 +1,67 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"viewer/main/http"
+	"viewer/main/utils"
+)
+
+func TestStreamingResponse(t *testing.T) {
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.URL.Path == "/moved" {
+			nethttp.Redirect(w, r, "/payload", nethttp.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"` + strings.Repeat("a", 1024) + `"}`))
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+
+	resp, err := utils.Response(nethttp.DefaultClient, server.URL+"/moved")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var model struct{ Name string }
+	if err := resp.DecodeJSON(&model); err != nil || len(model.Name) != 1024 {
+		t.Fatalf("Unexpected decoded model (%v)", err)
+	}
+	if resp.Url != server.URL+"/payload" {
+		t.Errorf("Unexpected final url '%s'.", resp.Url)
+	}
+	if timing := resp.Timing(); timing.Start.IsZero() || timing.Total < timing.Headers {
+		t.Errorf("Unexpected timing: %+v", timing)
+	}
+
+	resp, err = utils.Response(nethttp.DefaultClient, server.URL, http.WithMaxResponseSize(512))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body, err := resp.Bytes(); !errors.Is(err, http.ErrResponseTooLarge) || len(body) != 512 {
+		t.Errorf("Expected the body to be limited to 512 bytes, got %d (%v)", len(body), err)
+	}
+}
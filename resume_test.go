@@ -0,0 +1,161 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"viewer/main/download"
+	"viewer/main/http"
+)
+
+func TestResumableDownload(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 1000))
+	interrupt := true
+	ranges := make([]string, 0)
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		w.Header().Set("ETag", `"v1"`)
+		if interrupt {
+			// Send the first half, and then keep the download in progress until the client goes away.
+			w.Header().Set("Content-Length", "10000")
+			_, _ = w.Write(content[:5000])
+			w.(nethttp.Flusher).Flush()
+			<-r.Context().Done()
+			return
+		}
+		nethttp.ServeContent(w, r, "asset.bin", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+	directory := t.TempDir()
+	path := filepath.Join(directory, "asset.bin")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := download.FromContext(ctx, directory, "asset.bin", server.URL+"/asset"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the download to be interrupted, got '%v'.", err)
+	}
+	if stat, err := os.Stat(path + download.PartSuffix); err != nil || stat.Size() != 5000 {
+		t.Fatalf("Expected the part-file to be kept, got '%v'.", err)
+	}
+
+	interrupt = false
+	status, err := download.From(directory, "asset.bin", server.URL+"/asset")
+	if err != nil || !status.Downloaded() || status.Bytes != int64(len(content)) || !status.Resumed || status.HttpStatus != nethttp.StatusPartialContent {
+		t.Fatalf("Unexpected resumed download: %+v (%v)", status, err)
+	}
+	if digest := sha256.Sum256(content); status.Sha256 != hex.EncodeToString(digest[:]) {
+		t.Errorf("Expected the resumed download's checksum to include the part-file, got '%s'.", status.Sha256)
+	}
+	if written, err := os.ReadFile(path); err != nil || !bytes.Equal(written, content) {
+		t.Errorf("Unexpected downloaded content (%v)", err)
+	}
+	if ranges[len(ranges)-1] != "bytes=5000-" {
+		t.Errorf("Expected the download to be resumed, got range '%s'.", ranges[len(ranges)-1])
+	}
+	if _, err := os.Stat(path + download.PartSuffix); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the part-file to be renamed, got '%v'.", err)
+	}
+}
+
+func TestSegmentedDownload(t *testing.T) {
+	content := bytes.Repeat([]byte("segmented-content"), 200_000)
+	supportRanges := true
+	var mutex sync.Mutex
+	ranges := make([]string, 0)
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		mutex.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		mutex.Unlock()
+		if !supportRanges {
+			_, _ = w.Write(content)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		nethttp.ServeContent(w, r, "asset.bin", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+	directory := t.TempDir()
+
+	for _, supported := range []bool{true, false} {
+		supportRanges = supported
+		ranges = ranges[:0]
+		status, err := download.From(directory, "asset.bin", server.URL+"/asset", http.WithSegments(3))
+		if err != nil || status.Bytes != int64(len(content)) {
+			t.Fatalf("Unexpected download: %+v (%v)", status, err)
+		}
+		if written, err := os.ReadFile(filepath.Join(directory, "asset.bin")); err != nil || !bytes.Equal(written, content) {
+			t.Errorf("Unexpected downloaded content (%v)", err)
+		}
+		// The first request checks whether the server supports ranges.
+		if expected := map[bool]int{true: 4, false: 2}[supported]; len(ranges) != expected {
+			t.Errorf("Expected %d requests, got %v.", expected, ranges)
+		}
+	}
+}
+
+func TestFailedSegmentedDownload(t *testing.T) {
+	content := bytes.Repeat([]byte("segmented-content"), 200_000)
+	last := fmt.Sprintf("bytes=%d-", 2*(len(content)/3))
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		switch {
+		case r.Header.Get("Range") == "bytes=0-0":
+			nethttp.ServeContent(w, r, "asset.bin", time.Time{}, bytes.NewReader(content))
+		case strings.HasPrefix(r.Header.Get("Range"), last):
+			// The last segment reports that the content changed.
+			_, _ = w.Write(content)
+		default:
+			// Send a chunk, and then keep the segment in progress until the client goes away.
+			w.Header().Set("Content-Range", fmt.Sprintf("%s/%d", strings.Replace(r.Header.Get("Range"), "=", " ", 1), len(content)))
+			w.WriteHeader(nethttp.StatusPartialContent)
+			_, _ = w.Write(content[:1000])
+			w.(nethttp.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+	directory := t.TempDir()
+
+	result, err := download.From(directory, "asset.bin", server.URL+"/asset", http.WithSegments(3))
+	if !errors.Is(err, http.ErrNetwork) || !result.Error() {
+		t.Fatalf("Expected the changed segment to fail the download, got %+v (%v).", result, err)
+	}
+	if entries, err := os.ReadDir(directory); err != nil || len(entries) != 0 {
+		t.Errorf("Expected the part-file to be removed once every segment stopped, got %v (%v).", entries, err)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"viewer/main/download"
+	"viewer/main/http"
+	"viewer/main/repository"
+)
+
+func TestRetryOnTransientFailures(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		requests++
+		if requests%3 != 0 {
+			w.WriteHeader(nethttp.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"repo-viewer"}`))
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+	policy := http.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, RetryableStatus: []int{502}}
+
+	model, err := http.Request(repository.NewRepositoryRequest(ForRepository("aivruu", "repo-viewer")), 5*time.Second, http.WithRetry(policy))
+	if err != nil || model.Name != "repo-viewer" {
+		t.Fatal("Failed to request the repository after retrying.", err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 attempts, got %d.", requests)
+	}
+	_, err = http.Request(repository.NewRepositoryRequest(ForRepository("aivruu", "repo-viewer")), 5*time.Second, http.WithRetry(http.NoRetryPolicy))
+	var statusErr *http.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != nethttp.StatusBadGateway || requests != 4 {
+		t.Errorf("Expected a single failed attempt without retries, got %d attempts (%v).", requests-3, err)
+	}
+	status, err := download.From(t.TempDir(), "asset.bin", server.URL+"/asset", http.WithRetry(policy))
+	if err != nil || !status.Downloaded() || requests != 6 {
+		t.Errorf("Expected the download to succeed after retrying, status '%d' and %d attempts (%v).", status.Status, requests-4, err)
+	}
+}
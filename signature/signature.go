@@ -0,0 +1,238 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package signature
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+	"viewer/main/http"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Format This type represents the detached signatures' formats.
+type Format string
+
+const (
+	Minisign Format = "minisign" // Signatures created with minisign or signify-compatible tools (".minisig").
+	Ed25519  Format = "ed25519"  // Raw or base64-encoded Ed25519 signatures of the content (".sig"), if they're Recognized.
+	OpenPGP  Format = "openpgp"  // ASCII-armored OpenPGP signatures (".asc"), which can't be verified.
+)
+
+// formatsSuffixes The suffixes added to the assets' names by their signatures, for each Format.
+var formatsSuffixes = []struct {
+	suffix string
+	format Format
+}{
+	{".minisig", Minisign},
+	{".sig", Ed25519},
+	{".asc", OpenPGP},
+}
+
+// minisignAlgorithm The minisign's signature algorithm that signs the content, and the prehashed one, which signs its
+// BLAKE2b-512 checksum.
+const (
+	minisignAlgorithm          = "Ed"
+	minisignPrehashedAlgorithm = "ED"
+)
+
+// MaxMessageSize The maximum size of the content verified by the signatures that sign the content itself instead of its
+// checksum, the raw Ed25519 ones and the legacy minisign ones, as the whole content is read into memory to verify them.
+var MaxMessageSize int64 = 256 << 20
+
+// PublicKey This struct represents a trusted Ed25519 public key, with the minisign's key identifier when it's known.
+type PublicKey struct {
+	KeyId []byte
+	Key   ed25519.PublicKey
+}
+
+// ParsePublicKey This function parses the given public key, which is a minisign's public key (optionally preceded by its
+// "untrusted comment" line), or a base64-encoded Ed25519 public key.
+func ParsePublicKey(text string) (*PublicKey, error) {
+	line := lastLine(text)
+	decoded, err := base64.StdEncoding.DecodeString(line)
+	if err != nil {
+		return nil, fmt.Errorf("malformed public key '%s': %w", line, err)
+	}
+	switch {
+	case len(decoded) == ed25519.PublicKeySize:
+		return &PublicKey{Key: decoded}, nil
+	case len(decoded) == 2+8+ed25519.PublicKeySize && string(decoded[:2]) == minisignAlgorithm:
+		return &PublicKey{KeyId: decoded[2:10], Key: decoded[10:]}, nil
+	default:
+		return nil, fmt.Errorf("malformed public key '%s': unexpected length %d", line, len(decoded))
+	}
+}
+
+// Names This function returns the names of the assets that may provide the given asset-name's signature, with their Format.
+func Names(name string) map[string]Format {
+	names := make(map[string]Format, len(formatsSuffixes))
+	for _, candidate := range formatsSuffixes {
+		names[name+candidate.suffix] = candidate.format
+	}
+	return names
+}
+
+// IsSignature This function returns whether the given asset-name corresponds to a signature.
+func IsSignature(name string) bool {
+	for _, candidate := range formatsSuffixes {
+		if strings.HasSuffix(strings.ToLower(name), candidate.suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Supported This function returns whether the signatures using the given Format can be verified by Verify.
+func Supported(format Format) bool {
+	return format == Minisign || format == Ed25519
+}
+
+// Recognized This function returns whether the given signature's content can be verified by Verify using the given
+// Format, as the ".sig" suffix is also used by OpenPGP or cosign signatures, which are not Ed25519 ones.
+func Recognized(format Format, signature []byte) bool {
+	switch format {
+	case Minisign:
+		return true
+	case Ed25519:
+		_, ok := ed25519Signature(signature)
+		return ok
+	default:
+		return false
+	}
+}
+
+// Verify This function verifies that the given signature, using the specified Format, signs the given content with any of
+// the given trusted keys. It returns an error matching with errors.Is against http.ErrInvalidSignature if it doesn't, or
+// http.ErrUnsupportedFormat if the signature can't be verified, as it happens with OpenPGP ones, with the ones that are
+// not Recognized, or with the ones that sign a content larger than MaxMessageSize itself.
+func Verify(format Format, content io.Reader, signature []byte, keys []*PublicKey) error {
+	switch format {
+	case Minisign:
+		return verifyMinisign(content, signature, keys)
+	case Ed25519:
+		return verifyEd25519(content, signature, keys)
+	default:
+		return fmt.Errorf("%w: '%s'", http.ErrUnsupportedFormat, format)
+	}
+}
+
+// ed25519Signature This function returns the given raw, or base64-encoded, Ed25519 signature's bytes, or false if it's
+// not an Ed25519 signature.
+func ed25519Signature(signature []byte) ([]byte, bool) {
+	if len(signature) == ed25519.SignatureSize {
+		return signature, true
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil || len(decoded) != ed25519.SignatureSize {
+		return nil, false
+	}
+	return decoded, true
+}
+
+// verifyEd25519 This function verifies the given raw, or base64-encoded, Ed25519 signature of the given content.
+func verifyEd25519(content io.Reader, signature []byte, keys []*PublicKey) error {
+	signature, ok := ed25519Signature(signature)
+	if !ok {
+		return fmt.Errorf("%w: not an ed25519 signature", http.ErrUnsupportedFormat)
+	}
+	message, err := readMessage(content)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if ed25519.Verify(key.Key, message, signature) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no trusted key signed the content", http.ErrInvalidSignature)
+}
+
+// verifyMinisign This function verifies the given minisign's signature of the given content, including its trusted
+// comment's global signature.
+func verifyMinisign(content io.Reader, signature []byte, keys []*PublicKey) error {
+	lines := strings.Split(strings.ReplaceAll(string(signature), "\r\n", "\n"), "\n")
+	if len(lines) < 4 || !strings.HasPrefix(lines[0], "untrusted comment:") {
+		return fmt.Errorf("%w: malformed minisign signature", http.ErrInvalidSignature)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(decoded) != 2+8+ed25519.SignatureSize {
+		return fmt.Errorf("%w: malformed minisign signature", http.ErrInvalidSignature)
+	}
+	algorithm, keyId, contentSignature := string(decoded[:2]), decoded[2:10], decoded[10:]
+	trustedComment, found := strings.CutPrefix(lines[2], "trusted comment: ")
+	globalSignature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if !found || err != nil || len(globalSignature) != ed25519.SignatureSize {
+		return fmt.Errorf("%w: malformed minisign trusted comment", http.ErrInvalidSignature)
+	}
+	var message []byte
+	switch algorithm {
+	case minisignAlgorithm:
+		message, err = readMessage(content)
+	case minisignPrehashedAlgorithm:
+		// The hash only fails to be created with keys longer than 64 bytes.
+		digest, _ := blake2b.New512(nil)
+		_, err = io.Copy(digest, content)
+		message = digest.Sum(nil)
+	default:
+		return fmt.Errorf("%w: minisign algorithm '%s'", http.ErrUnsupportedFormat, algorithm)
+	}
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if key.KeyId != nil && !bytes.Equal(key.KeyId, keyId) {
+			continue
+		}
+		if !ed25519.Verify(key.Key, message, contentSignature) {
+			continue
+		}
+		if !ed25519.Verify(key.Key, append(bytes.Clone(contentSignature), trustedComment...), globalSignature) {
+			return fmt.Errorf("%w: the trusted comment was modified", http.ErrInvalidSignature)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: no trusted key signed the content (key id %X)", http.ErrInvalidSignature, keyId)
+}
+
+// readMessage This function reads the given content to verify its signature, failing with http.ErrUnsupportedFormat if
+// it's larger than MaxMessageSize.
+func readMessage(content io.Reader) ([]byte, error) {
+	message, err := io.ReadAll(io.LimitReader(content, MaxMessageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(message)) > MaxMessageSize {
+		return nil, fmt.Errorf("%w: content larger than %d bytes signed without its checksum", http.ErrUnsupportedFormat, MaxMessageSize)
+	}
+	return message, nil
+}
+
+// lastLine This function returns the given text's last non-empty line, without surrounding spaces.
+func lastLine(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
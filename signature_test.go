@@ -0,0 +1,160 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"viewer/main/download"
+	"viewer/main/http"
+	"viewer/main/repository"
+	"viewer/main/signature"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// minisign This function signs the given content as minisign does with its default prehashed algorithm.
+func minisign(key ed25519.PrivateKey, keyId []byte, content []byte) string {
+	digest := blake2b.Sum512(content)
+	contentSignature := ed25519.Sign(key, digest[:])
+	trustedComment := "timestamp:1700000000\tfile:app.bin"
+	globalSignature := ed25519.Sign(key, append(contentSignature, trustedComment...))
+	return "untrusted comment: signature from minisign secret key\n" +
+		base64.StdEncoding.EncodeToString(append(append([]byte("ED"), keyId...), contentSignature...)) + "\n" +
+		"trusted comment: " + trustedComment + "\n" +
+		base64.StdEncoding.EncodeToString(globalSignature) + "\n"
+}
+
+func TestSignatureVerification(t *testing.T) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(nil)
+	keyId := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	minisignKey := "untrusted comment: minisign public key\n" +
+		base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyId...), publicKey...))
+	content := []byte("release-binary")
+	files := map[string]string{
+		"/app.bin":              string(content),
+		"/app.bin.minisig":      minisign(privateKey, keyId, content),
+		"/raw.bin":              string(content),
+		"/raw.bin.sig":          base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, content)),
+		"/tampered.bin":         "tampered-binary",
+		"/tampered.bin.minisig": minisign(privateKey, keyId, content),
+		"/pgp.bin":              string(content),
+		"/pgp.bin.asc":          "-----BEGIN PGP SIGNATURE-----",
+		"/both.bin":             string(content),
+		"/both.bin.asc":         "-----BEGIN PGP SIGNATURE-----",
+		"/both.bin.sig":         base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, []byte("other-binary"))),
+		"/both.bin.minisig":     minisign(privateKey, keyId, content),
+		"/gpg.bin":              string(content),
+		"/gpg.bin.sig":          "\x89\x01\x33\x04\x00\x01\x08\x00\x1d\x16\x21\x04" + strings.Repeat("\xa5", 300),
+		"/cosign.bin":           string(content),
+		"/cosign.bin.sig":       base64.StdEncoding.EncodeToString([]byte(strings.Repeat("cosign-signature", 5))),
+		"/cosign.bin.minisig":   minisign(privateKey, keyId, content),
+		"/unsigned.bin":         string(content),
+	}
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		_, _ = w.Write([]byte(files[r.URL.Path]))
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+	model := &repository.GithubReleaseModel{ApiUrl: server.URL + "/repos/aivruu/repo-viewer/releases/1"}
+	for path := range files {
+		model.Assets = append(model.Assets, repository.Asset{Name: path[1:], Url: server.URL + path})
+	}
+	// The repositories' names are case-insensitive.
+	http.DefaultConfig.TrustKey("Aivruu/Repo-Viewer", minisignKey)
+	http.DefaultConfig.TrustKey("aivruu/repo-viewer", base64.StdEncoding.EncodeToString(publicKey))
+	directory := t.TempDir()
+
+	// The signatures that can't be verified are skipped when another one is valid.
+	expectations := map[string]error{
+		"app.bin":      nil,
+		"raw.bin":      nil,
+		"both.bin":     nil,
+		"cosign.bin":   nil,
+		"gpg.bin":      http.ErrUnsupportedFormat,
+		"tampered.bin": http.ErrInvalidSignature,
+		"pgp.bin":      http.ErrUnsupportedFormat,
+		"unsigned.bin": http.ErrNoSignature,
+	}
+	for index, asset := range model.Assets {
+		expected, found := expectations[asset.Name]
+		if !found {
+			continue
+		}
+		_, err := model.Download(directory, index)
+		if !errors.Is(err, expected) {
+			t.Errorf("Expected '%v' for '%s', got '%v'.", expected, asset.Name, err)
+		}
+		_, statErr := os.Stat(filepath.Join(directory, asset.Name))
+		if (expected == nil) != (statErr == nil) {
+			t.Errorf("Unexpected file state for '%s': %v", asset.Name, statErr)
+		}
+	}
+
+	// The content is verified before it replaces the existing file.
+	signature.MaxMessageSize = 8
+	t.Cleanup(func() { signature.MaxMessageSize = 256 << 20 })
+	if err := os.WriteFile(filepath.Join(directory, "raw.bin"), []byte("existing-binary"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for index, asset := range model.Assets {
+		if asset.Name != "raw.bin" {
+			continue
+		}
+		if _, err := model.Download(directory, index); !errors.Is(err, http.ErrUnsupportedFormat) {
+			t.Errorf("Expected the content larger than the maximum message size to be unsupported, got '%v'.", err)
+		}
+		if existing, err := os.ReadFile(filepath.Join(directory, "raw.bin")); err != nil || string(existing) != "existing-binary" {
+			t.Errorf("Expected the existing file to be kept, got '%s' (%v).", existing, err)
+		}
+		if _, err := os.Stat(filepath.Join(directory, "raw.bin"+download.PartSuffix)); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Expected the unverified part-file to be removed, got '%v'.", err)
+		}
+	}
+
+	// The pinned keys can't be ignored when the release's repository is not known.
+	model.ApiUrl = ""
+	for index, asset := range model.Assets {
+		if asset.Name == "app.bin" {
+			if _, err := model.Download(t.TempDir(), index); !errors.Is(err, http.ErrNoSignature) {
+				t.Errorf("Expected the release without repository to fail, got '%v'.", err)
+			}
+		}
+	}
+
+	// Repositories without pinned keys are not verified.
+	model.ApiUrl = server.URL + "/repos/aivruu/other/releases/1"
+	for index, asset := range model.Assets {
+		if asset.Name == "unsigned.bin" {
+			if _, err := model.Download(directory, index); err != nil {
+				t.Errorf("Expected the unsigned asset to be downloaded, got '%v'.", err)
+			}
+		}
+	}
+}
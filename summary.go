@@ -0,0 +1,72 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// assetReport This struct describes the result of an asset's download, as shown by the downloads' summary.
+type assetReport struct {
+	Name     string
+	Bytes    int64
+	Duration time.Duration
+	Status   string
+	Err      error // The download's failure cause, nil if it was successful.
+}
+
+// failedReport This function creates a new assetReport for the given asset's failed download.
+func failedReport(name string, duration time.Duration, err error) assetReport {
+	status := "failed"
+	if errors.Is(err, context.Canceled) {
+		status = "cancelled"
+	}
+	return assetReport{Name: name, Duration: duration, Status: status, Err: err}
+}
+
+// printSummary This function writes a table with the given downloads' reports into the given writer, followed by the
+// totals, and returns the amount of failed downloads.
+func printSummary(out io.Writer, reports []assetReport) int {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "NAME\tBYTES\tDURATION\tSTATUS\tERROR")
+	failed := 0
+	var total int64
+	for _, report := range reports {
+		bytes, message := formatBytes(report.Bytes), "-"
+		if report.Err != nil {
+			failed++
+			bytes, message = "-", report.Err.Error()
+		}
+		total += report.Bytes
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", report.Name, bytes, report.Duration.Round(time.Millisecond),
+			report.Status, message)
+	}
+	_ = writer.Flush()
+	_, _ = fmt.Fprintf(out, "%d of %d assets downloaded (%s), %d failed.\n", len(reports)-failed, len(reports),
+		formatBytes(total), failed)
+	return failed
+}
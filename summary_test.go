@@ -0,0 +1,90 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"viewer/main/http"
+	"viewer/main/repository"
+)
+
+func TestConcurrentDownloadsSummary(t *testing.T) {
+	var active, maxActive atomic.Int32
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		current := active.Add(1)
+		defer active.Add(-1)
+		for previous := maxActive.Load(); current > previous && !maxActive.CompareAndSwap(previous, current); {
+			previous = maxActive.Load()
+		}
+		time.Sleep(50 * time.Millisecond)
+		if r.URL.Path == "/missing.bin" {
+			nethttp.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("content-of" + r.URL.Path))
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+	model := &repository.GithubReleaseModel{}
+	for _, name := range []string{"a.bin", "missing.bin", "b.bin", "c.bin", "d.bin"} {
+		model.Assets = append(model.Assets, repository.Asset{Name: name, Url: server.URL + "/" + name})
+	}
+
+	reports := downloadAssets(context.Background(), t.TempDir(), model, []int{0, 1, 2, 3, 4}, nil, 2)
+	if maxActive.Load() != 2 {
+		t.Errorf("Expected 2 concurrent downloads, got %d", maxActive.Load())
+	}
+	for index, report := range reports {
+		if report.Name != model.Assets[index].Name {
+			t.Errorf("Unexpected report order: %+v", reports)
+		}
+		if failed := report.Name == "missing.bin"; failed != (report.Err != nil) || (failed && !errors.Is(report.Err, http.ErrNotFound)) {
+			t.Errorf("Unexpected report: %+v", report)
+		}
+	}
+	if reports[0].Bytes != int64(len("content-of/a.bin")) || reports[0].Status != "downloaded" {
+		t.Errorf("Unexpected successful report: %+v", reports[0])
+	}
+
+	var summary strings.Builder
+	if failed := printSummary(&summary, reports); failed != 1 {
+		t.Errorf("Expected 1 failed download, got %d", failed)
+	}
+	if !strings.Contains(summary.String(), "4 of 5 assets downloaded") || !strings.Contains(summary.String(), "failed") {
+		t.Errorf("Unexpected summary:\n%s", summary.String())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, report := range downloadAssets(ctx, t.TempDir(), model, []int{0, 2}, nil, 1) {
+		if report.Status != "cancelled" || !errors.Is(report.Err, context.Canceled) {
+			t.Errorf("Expected a cancelled report, got %+v", report)
+		}
+	}
+}
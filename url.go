@@ -21,30 +21,16 @@
 
 package main
 
-import (
-	"fmt"
-	"strings"
-)
+import "viewer/main/http"
 
-const (
-	GithubApiUrl        = "https://api.github.com/repos/%s/%s"
-	GithubApiReleaseUrl = GithubApiUrl + "/releases/tags/%s"
-)
-
-// ForRepository This function formats the GithubApiUrl to include the author and repository specified to create a valid
-// url for a request.
+// ForRepository This function uses the http.DefaultConfig's base URL to create a valid url for a request to the author's
+// repository.
 func ForRepository(author, repository string) string {
-	return fmt.Sprintf(GithubApiUrl, author, repository)
+	return http.DefaultConfig.RepositoryUrl(author, repository)
 }
 
-// ForRelease This function formats the GithubApiReleaseUrl to include the author, repository and tag specified to create a valid
-// url for a request.
+// ForRelease This function uses the http.DefaultConfig's base URL to create a valid url for a request to the repository's
+// release with the specified tag, or to its latest release if the tag is 'latest'.
 func ForRelease(author, repository, tag string) string {
-	var version string
-	if strings.Index(tag, "latest") < 0 {
-		version = tag
-	} else {
-		version = strings.Replace(GithubApiReleaseUrl, "tags/", "", 1)
-	}
-	return fmt.Sprintf(GithubApiReleaseUrl, author, repository, version)
+	return http.DefaultConfig.ReleaseUrl(author, repository, tag)
 }
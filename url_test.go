@@ -0,0 +1,65 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"testing"
+	"viewer/main/http"
+)
+
+func TestConfigurableBaseUrl(t *testing.T) {
+	config, err := http.NewConfig("https://github.example.com/api/v3/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url := config.RepositoryUrl("aivruu", "repo-viewer"); url != "https://github.example.com/api/v3/repos/aivruu/repo-viewer" {
+		t.Errorf("Unexpected repository url: %s", url)
+	}
+	if url := config.ReleaseUrl("aivruu", "repo-viewer", "latest"); url != "https://github.example.com/api/v3/repos/aivruu/repo-viewer/releases/latest" {
+		t.Errorf("Unexpected latest release url: %s", url)
+	}
+	if url := config.ReleaseUrl("aivruu", "repo-viewer", "v3.4.7"); url != "https://github.example.com/api/v3/repos/aivruu/repo-viewer/releases/tags/v3.4.7" {
+		t.Errorf("Unexpected release url: %s", url)
+	}
+	if !config.ValidDownloadUrl("https://github.example.com/aivruu/repo-viewer/releases/download/v3.4.7/viewer.jar") {
+		t.Error("Enterprise download url should be valid.")
+	}
+	if config.ValidDownloadUrl("https://github.com/aivruu/repo-viewer/releases/download/v3.4.7/viewer.jar") {
+		t.Error("Public GitHub download url should not be valid for an enterprise base url.")
+	}
+	if _, err := http.NewConfig("ftp://github.example.com"); err == nil {
+		t.Error("Base url with unsupported scheme should be rejected.")
+	}
+}
+
+func TestDefaultBaseUrl(t *testing.T) {
+	config, _ := http.NewConfig(http.DefaultBaseUrl)
+	if !config.ValidDownloadUrl("https://github.com/aivruu/repo-viewer/releases/download/v3.4.7/viewer.jar") {
+		t.Error("GitHub download url should be valid.")
+	}
+	if !config.ValidDownloadUrl("https://api.github.com/repos/aivruu/repo-viewer/releases/assets/1") {
+		t.Error("GitHub API asset url should be valid.")
+	}
+	if config.ValidDownloadUrl("http://github.com/aivruu/repo-viewer/releases/download/v3.4.7/viewer.jar") {
+		t.Error("Download url with different scheme should not be valid.")
+	}
+}
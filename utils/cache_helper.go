@@ -0,0 +1,94 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package utils
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+	"viewer/main/cache"
+)
+
+// setConditionalHeaders This function sets the headers used to revalidate the given cache.Entry, so the server answers with a
+// 304 status-code if the cached body is still up-to-date.
+func setConditionalHeaders(header http.Header, entry *cache.Entry) {
+	if entry.ETag != "" {
+		header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		header.Set("If-Modified-Since", entry.LastModified)
+	}
+}
+
+// entryFrom This function creates a new cache.Entry for the given url's response body, using the validators provided by the
+// response's headers.
+func entryFrom(url string, header http.Header, body string) *cache.Entry {
+	return &cache.Entry{
+		Url:          url,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+		Link:         header.Get("Link"),
+		StoredAt:     time.Now(),
+		Body:         body,
+	}
+}
+
+// cachingBody This struct wraps a response's body to store it into the cache when it's completely read, a body that is
+// closed before, or whose reading fails, is not stored.
+type cachingBody struct {
+	reader io.ReadCloser
+	buffer bytes.Buffer
+	store  *cache.Store
+	key    string
+	entry  *cache.Entry
+}
+
+// newCachingBody This function creates a new cachingBody that stores the given entry with the read body into the given
+// cache.Store using the specified key.
+func newCachingBody(reader io.ReadCloser, store *cache.Store, key string, entry *cache.Entry) *cachingBody {
+	return &cachingBody{reader: reader, store: store, key: key, entry: entry}
+}
+
+func (b *cachingBody) Read(p []byte) (int, error) {
+	n, err := b.reader.Read(p)
+	if b.entry == nil {
+		return n, err
+	}
+	b.buffer.Write(p[:n])
+	if err == io.EOF {
+		b.entry.Body = b.buffer.String()
+		// The cache's failures are ignored, the response is just not revalidated later.
+		_ = b.store.Save(b.key, b.entry)
+	}
+	if err != nil {
+		b.entry = nil
+		b.buffer.Reset()
+	}
+	return n, err
+}
+
+func (b *cachingBody) Close() error {
+	b.entry = nil
+	b.buffer.Reset()
+	return b.reader.Close()
+}
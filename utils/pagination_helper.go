@@ -0,0 +1,69 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package utils
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	vhttp "viewer/main/http"
+	"viewer/main/repository/codec"
+)
+
+// Paginate This function returns an iterator over the models provided by the given list endpoint's url, the pages are
+// requested lazily with ResponseContext and stream-deserialized with the codec.Provider registered for the models' slice,
+// following the Link header's next page until there are no more pages, the vhttp.PageOptions' MaxItems is reached, or the
+// iteration is stopped. Any request or deserialization error is provided with the zero-value model, ending the iteration.
+func Paginate[M any](ctx context.Context, client *http.Client, url string, page vhttp.PageOptions, options ...vhttp.RequestOption) iter.Seq2[M, error] {
+	return func(yield func(M, error) bool) {
+		var zero M
+		next, err := page.FirstPageUrl(url)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		provided := 0
+		for next != "" {
+			resp, err := ResponseContext(ctx, client, next, options...)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			models, err := codec.Lookup[[]M]().Decode(resp.Body())
+			_ = resp.Close()
+			if err != nil {
+				yield(zero, &vhttp.DecodeError{Url: next, Err: err})
+				return
+			}
+			for _, model := range *models {
+				if !yield(model, nil) {
+					return
+				}
+				provided++
+				if page.MaxItems > 0 && provided >= page.MaxItems {
+					return
+				}
+			}
+			next = vhttp.NextPageUrl(resp.Header)
+		}
+	}
+}
@@ -22,44 +22,105 @@
 package utils
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 	"viewer/main/async"
+	"viewer/main/cache"
 	vhttp "viewer/main/http"
 )
 
-// AsyncResponseWith This function makes a request to the given url using the given http.Client and will return an
-// async.Future, this object's function may return a http.ResponseModel, or null depending on operation success.
-func asyncResponse(client *http.Client, url string) async.Future[vhttp.ResponseModel] {
-	return async.NewFuture(func() *vhttp.ResponseModel {
-		resp, err := client.Get(url)
-		if err != nil {
-			fmt.Println("Error during request: ", err)
-			return nil
+// asyncResponse This function makes a request to the given url using the given http.Client and will return an
+// async.Future, which is completed with the http.ResponseModel, or with the failure's cause. The response's body is
+// not read, it's limited to the given maximum size, and it is stored into the cache when it's completely read. The request
+// is aborted when the given context is done.
+func asyncResponse(ctx context.Context, client *http.Client, url string, options vhttp.RequestOptions) *async.Future[*vhttp.ResponseModel] {
+	config := vhttp.DefaultConfig
+	return async.NewFutureContext(ctx, func(ctx context.Context) (*vhttp.ResponseModel, error) {
+		header := withHeaders(http.Header{"Accept": {"application/vnd.github+json"}}, options.Header)
+		store := config.Cache
+		key := cache.Key(url, config.Token)
+		var entry *cache.Entry
+		if store != nil {
+			// The cache's failures are ignored, the request is just made without revalidation.
+			entry, _ = store.Load(key)
+		}
+		if entry != nil {
+			setConditionalHeaders(header, entry)
 		}
-		read, err := io.ReadAll(resp.Body)
+		start := time.Now()
+		resp, err := send(ctx, config, client, url, header, options.Retry)
 		if err != nil {
-			fmt.Println("Error during reading: ", err)
-			return nil
+			return nil, err
 		}
-		// Close body after reading.
-		defer func(Body io.ReadCloser) {
-			if err := Body.Close(); err != nil {
-				fmt.Println("Error during Body closing: ", err)
+		finalUrl := url
+		if resp.Request != nil {
+			finalUrl = resp.Request.URL.String()
+		}
+		body := resp.Body
+		statusCode := resp.StatusCode
+		cached := false
+		if entry != nil && resp.StatusCode == http.StatusNotModified {
+			_ = resp.Body.Close()
+			body = io.NopCloser(strings.NewReader(entry.Body))
+			statusCode = vhttp.ResponseOkStatus
+			cached = true
+			if resp.Header.Get("Link") == "" && entry.Link != "" {
+				resp.Header.Set("Link", entry.Link)
+			}
+		} else if store != nil && resp.StatusCode == vhttp.ResponseOkStatus {
+			if entry := entryFrom(url, resp.Header, ""); entry.Revalidable() {
+				body = newCachingBody(body, store, key, entry)
 			}
-		}(resp.Body)
-		return &vhttp.ResponseModel{JSON: string(read), StatusCode: resp.StatusCode, Body: resp.Body}
+		}
+		model := vhttp.NewResponseModel(statusCode, resp.Header, finalUrl, body, start, options.MaxResponseSize)
+		model.Cached = cached
+		return model, nil
 	})
 }
 
 // Response This function calls internally to the asyncResponse and when is available, it will return the http.ResponseModel
-// provided by that function.
-func Response(client *http.Client, url string) *vhttp.ResponseModel {
-	f := asyncResponse(client, url)
-	// Return [ResponseModel] object when available.
-	return f.Get()
+// provided by that function, whose body must be closed by the caller. The request's settings are taken from the vhttp.DefaultConfig, modified by the given options.
+// If the response is rejected due to an exceeded rate limit, a vhttp.RateLimitError is returned, or the request is repeated
+// after the rate limit's reset when the vhttp.RateLimitWait policy is used. A vhttp.StatusError is returned for any other
+// unsuccessful status-code, and a vhttp.NetworkError if the request couldn't be made.
+func Response(client *http.Client, url string, options ...vhttp.RequestOption) (*vhttp.ResponseModel, error) {
+	return ResponseContext(context.Background(), client, url, options...)
+}
+
+// ResponseContext This function realizes the same execution that Response, the request and any wait between attempts are
+// aborted when the given context is done, returning the context's error.
+func ResponseContext(ctx context.Context, client *http.Client, url string, options ...vhttp.RequestOption) (*vhttp.ResponseModel, error) {
+	requestOptions := vhttp.DefaultConfig.Options(options...)
+	for {
+		// Return [ResponseModel] object when available.
+		resp, err := asyncResponse(ctx, client, url, requestOptions).Get()
+		if err != nil {
+			return nil, err
+		}
+		if !vhttp.RateLimited(resp.StatusCode, &resp.RateLimit) {
+			if !successful(resp.StatusCode) {
+				// Read the error's payload (if any) to provide the API's message.
+				body, _ := io.ReadAll(io.LimitReader(resp.Body(), 64*1024))
+				_ = resp.Close()
+				return nil, vhttp.NewStatusError(url, resp.StatusCode, body)
+			}
+			return resp, nil
+		}
+		_ = resp.Close()
+		limitErr := &vhttp.RateLimitError{Url: url, StatusCode: resp.StatusCode, RateLimit: resp.RateLimit}
+		wait := resp.RateLimit.Wait()
+		if requestOptions.RateLimitPolicy != vhttp.RateLimitWait || wait <= 0 || wait > requestOptions.MaxRateLimitWait {
+			return nil, limitErr
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
 }
 
 // ValidateAndModifyTimeout This function validates the given client and then modifies the client's timeout. If the http.Client
@@ -73,15 +134,142 @@ func ValidateAndModifyTimeout(client *http.Client, timeout time.Duration) *http.
 	return client
 }
 
-// OriginalResponse This function makes an async request to the given url, and returns the built-in http.Response object,
-// and not a http.ResponseModel.
-func OriginalResponse(url string) async.Future[http.Response] {
-	return async.NewFuture(func() *http.Response {
-		resp, err := http.Get(url)
+// OriginalResponse This function makes a request to the given url accepting raw content (as required by the API's assets'
+// urls) using the vhttp.DefaultConfig's retry policy, or the one specified by the given options, and returns the built-in
+// http.Response object, and not a http.ResponseModel. The response's body must be closed by the caller. As it happens with
+// Response, a vhttp.StatusError, vhttp.RateLimitError or vhttp.NetworkError is returned if the request fails.
+func OriginalResponse(url string, options ...vhttp.RequestOption) (*http.Response, error) {
+	return OriginalResponseContext(context.Background(), url, options...)
+}
+
+// OriginalResponseContext This function realizes the same execution that OriginalResponse, the request is aborted when the
+// given context is done, including the reading of the response's body.
+func OriginalResponseContext(ctx context.Context, url string, options ...vhttp.RequestOption) (*http.Response, error) {
+	config := vhttp.DefaultConfig
+	requestOptions := config.Options(options...)
+	header := withHeaders(http.Header{"Accept": {"application/octet-stream"}}, requestOptions.Header)
+	resp, err := async.NewFutureContext(ctx, func(ctx context.Context) (*http.Response, error) {
+		return send(ctx, config, http.DefaultClient, url, header, requestOptions.Retry)
+	}).Get()
+	if err != nil {
+		return nil, err
+	}
+	if successful(resp.StatusCode) {
+		return resp, nil
+	}
+	// Read the error's payload (if any) to provide the API's message.
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if rateLimit := vhttp.RateLimitFrom(resp.Header); vhttp.RateLimited(resp.StatusCode, &rateLimit) {
+		return nil, &vhttp.RateLimitError{Url: url, StatusCode: resp.StatusCode, RateLimit: rateLimit}
+	}
+	return nil, vhttp.NewStatusError(url, resp.StatusCode, body)
+}
+
+// withHeaders This function sets the given additional headers into the specified header, replacing the existing values,
+// and returns it.
+func withHeaders(header http.Header, additional http.Header) http.Header {
+	for name, values := range additional {
+		header[http.CanonicalHeaderKey(name)] = values
+	}
+	return header
+}
+
+// successful This function returns whether the given status-code is a successful (2xx) one.
+func successful(statusCode int) bool {
+	return statusCode >= 200 && statusCode < 300
+}
+
+// networkError This function returns the context's error if the given context is done, as it is the failure's cause,
+// otherwise, the given error wrapped into a vhttp.NetworkError for the given url.
+func networkError(ctx context.Context, url string, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return &vhttp.NetworkError{Url: url, Err: err}
+}
+
+// send This function sends a GET request for the given url with the specified headers, authorized by the given vhttp.Config,
+// repeating it according to the given vhttp.RetryPolicy while it fails due to a network error or a retryable status-code.
+// When no more attempts are allowed, the last response or error is returned.
+func send(ctx context.Context, config *vhttp.Config, client *http.Client, url string, header http.Header, retry vhttp.RetryPolicy) (*http.Response, error) {
+	client = redirectSafeClient(client)
+	for attempt := 1; ; attempt++ {
+		req, err := newRequest(ctx, config, url, header)
 		if err != nil {
-			fmt.Println("Error during request: ", err)
-			return nil
+			return nil, fmt.Errorf("request creation: %w", err)
 		}
-		return resp
-	})
+		resp, err := client.Do(req)
+		var retryAfter time.Duration
+		if err == nil {
+			if !retry.Retryable(resp.StatusCode) {
+				return resp, nil
+			}
+			retryAfter = vhttp.RateLimitFrom(resp.Header).RetryAfter
+		}
+		delay, ok := retry.Delay(attempt, retryAfter)
+		if !ok || ctx.Err() != nil {
+			if err != nil {
+				return nil, networkError(ctx, url, err)
+			}
+			return resp, nil
+		}
+		if resp != nil {
+			// Discard the failed response so the connection can be reused for the next attempt.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sleep This function pauses the current goroutine for the given duration, returning the context's error if the given
+// context is done before.
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// newRequest This function creates a new GET request bound to the given context for the given url with a copy of the
+// specified headers, the request is authorized using the given vhttp.Config's token when the url belongs to the configured
+// GitHub instance.
+func newRequest(ctx context.Context, config *vhttp.Config, url string, header http.Header) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header.Clone()
+	config.Authorize(req)
+	return req, nil
+}
+
+// redirectSafeClient This function returns a shallow copy of the given http.Client that removes the Authorization header
+// from redirected requests to a different host or scheme than the original request's ones, so the token is never leaked to
+// the redirects' targets, such as the storage servers used for the assets' downloads.
+func redirectSafeClient(client *http.Client) *http.Client {
+	safeClient := *client
+	checkRedirect := client.CheckRedirect
+	safeClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if req.URL.Host != via[0].URL.Host || req.URL.Scheme != via[0].URL.Scheme {
+			req.Header.Del("Authorization")
+		}
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+	return &safeClient
 }
@@ -0,0 +1,178 @@
+// Copyright 2024 aivruu
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the
+// Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"viewer/main/download"
+	"viewer/main/http"
+	"viewer/main/repository"
+)
+
+// failingWriter This struct is a writer that always fails, like a closed pipe.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestDownloadToWriter(t *testing.T) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(nil)
+	keyId := []byte{8, 7, 6, 5, 4, 3, 2, 1}
+	content := bytes.Repeat([]byte("streamed-content"), 16<<10)
+	files := map[string][]byte{
+		"/app.bin":              content,
+		"/app.bin.minisig":      []byte(minisign(privateKey, keyId, content)),
+		"/tampered.bin":         []byte("tampered-content"),
+		"/tampered.bin.minisig": []byte(minisign(privateKey, keyId, content)),
+		"/unsigned.bin":         content,
+	}
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		_, _ = w.Write(files[r.URL.Path])
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+
+	var output bytes.Buffer
+	status, err := download.ToWriter(&output, "app.bin", server.URL+"/app.bin")
+	if err != nil || status.Bytes != int64(len(content)) || !bytes.Equal(output.Bytes(), content) {
+		t.Fatalf("Unexpected download to writer: %+v (%v)", status, err)
+	}
+	digest := sha256.Sum256(content)
+	if _, err := download.ToWriter(&output, "app.bin", server.URL+"/app.bin", http.WithChecksum(hex.EncodeToString(digest[:]))); err != nil {
+		t.Errorf("Expected the checksum to match, got '%v'.", err)
+	}
+	if _, err := download.ToWriter(&output, "tampered.bin", server.URL+"/tampered.bin", http.WithChecksum(hex.EncodeToString(digest[:]))); !errors.Is(err, http.ErrChecksumMismatch) {
+		t.Errorf("Expected the checksum mismatch, got '%v'.", err)
+	}
+	if _, err := download.ToWriter(failingWriter{}, "app.bin", server.URL+"/app.bin"); !errors.Is(err, http.ErrFileSystem) {
+		t.Errorf("Expected the writer's failure, got '%v'.", err)
+	}
+	if _, err := download.ToWriter(&output, "app.bin", "https://example.com/app.bin"); !errors.Is(err, http.ErrInvalidAssetUrl) {
+		t.Errorf("Expected the invalid url error, got '%v'.", err)
+	}
+
+	model := &repository.GithubReleaseModel{ApiUrl: server.URL + "/repos/aivruu/repo-viewer/releases/1"}
+	for _, name := range []string{"app.bin", "app.bin.minisig", "tampered.bin", "tampered.bin.minisig", "unsigned.bin"} {
+		model.Assets = append(model.Assets, repository.Asset{Name: name, Url: server.URL + "/" + name})
+	}
+	http.DefaultConfig.TrustKey("aivruu/repo-viewer", "untrusted comment: minisign public key\n"+
+		base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyId...), publicKey...)))
+	expectations := map[int]error{0: nil, 2: http.ErrInvalidSignature, 4: http.ErrNoSignature}
+	for index, expected := range expectations {
+		output.Reset()
+		result, err := model.DownloadTo(&output, index)
+		if !errors.Is(err, expected) {
+			t.Errorf("Expected '%v' for '%s', got '%v'.", expected, model.Assets[index].Name, err)
+		}
+		if expected == nil && (result.Bytes != int64(len(content)) || !bytes.Equal(output.Bytes(), content)) {
+			t.Errorf("Unexpected content written for '%s': %+v", model.Assets[index].Name, result)
+		}
+	}
+	if _, err := model.DownloadTo(&output, 9); !errors.Is(err, http.ErrInvalidAssetIndex) {
+		t.Errorf("Expected the invalid index error, got '%v'.", err)
+	}
+}
+
+func TestWriteAssetOutput(t *testing.T) {
+	content := []byte("written-content")
+	requests := 0
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.URL.Path != "/app.bin" {
+			nethttp.NotFound(w, r)
+			return
+		}
+		requests++
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+	useTestConfig(t, server, "")
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(File *os.File) {
+		_ = File.Close()
+	}(devNull)
+	model := &repository.GithubReleaseModel{ApiUrl: server.URL + "/repos/aivruu/repo-viewer/releases/1", Assets: []repository.Asset{
+		{Name: "app.bin", Url: server.URL + "/app.bin"},
+		{Name: "missing.bin", Url: server.URL + "/missing.bin"},
+	}}
+	directory := t.TempDir()
+	output := filepath.Join(directory, "out.bin")
+	if err := os.WriteFile(output, []byte("existing-content"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	tests := map[string]struct {
+		index    int
+		policy   http.ConflictPolicy
+		expected error
+		requests int
+		path     string
+		content  string
+		kept     bool
+	}{
+		"failed":         {index: 1, policy: http.ConflictOverwrite, expected: http.ErrNotFound, path: output, content: "existing-content"},
+		"fail":           {index: 0, policy: http.ConflictFail, expected: http.ErrFileExists, path: output, content: "existing-content"},
+		"rename":         {index: 0, policy: http.ConflictRename, requests: 1, path: filepath.Join(directory, "out-1.bin"), content: string(content)},
+		"skip-different": {index: 0, policy: http.ConflictSkipIdentical, requests: 1, path: output, content: string(content)},
+		"skip-identical": {index: 0, policy: http.ConflictSkipIdentical, requests: 1, path: output, content: string(content), kept: true},
+		"overwrite":      {index: 0, policy: http.ConflictOverwrite, requests: 1, path: output, content: string(content)},
+	}
+	for _, name := range []string{"failed", "fail", "rename", "skip-different", "skip-identical", "overwrite"} {
+		test := tests[name]
+		http.DefaultConfig.ConflictPolicy = test.policy
+		requests = 0
+		if err := os.Chtimes(output, past, past); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeAsset(context.Background(), output, model, test.index, newProgressPrinter(devNull, 1)); !errors.Is(err, test.expected) {
+			t.Errorf("%s: expected '%v', got '%v'.", name, test.expected, err)
+		}
+		if requests != test.requests {
+			t.Errorf("%s: expected %d requests, got %d.", name, test.requests, requests)
+		}
+		if stat, err := os.Stat(output); test.kept && (err != nil || !stat.ModTime().Equal(past)) {
+			t.Errorf("%s: expected the identical file to be kept (%v).", name, err)
+		}
+		if written, err := os.ReadFile(test.path); err != nil || string(written) != test.content {
+			t.Errorf("%s: expected '%s' to contain '%s', got '%s' (%v).", name, test.path, test.content, written, err)
+		}
+		if _, err := os.Stat(output + download.PartSuffix); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("%s: expected the part-file to be removed, got '%v'.", name, err)
+		}
+	}
+}